@@ -1,33 +1,136 @@
 package client
 
-import "encoding/json"
+import (
+	"encoding/json"
+	"time"
+)
 
 type SyncInitInfo struct {
-	// Format of data. Can be `json` or `binary`.
+	// Format of data. Can be `json`, `binary` or `raw` (see WriteRawKV).
 	Format string `json:"format"`
 
-	// DoDelete makes the sync delete unseen keys. No deletions if false (the default case).
+	// DoDelete makes the sync delete unseen keys. No deletions if false (the default case) except
+	// the delete records. In delta mode, it allows delete records instead.
 	DoDelete bool `json:"doDelete"`
 
+	// Mode is `full` (the default: the values are the whole dataset) or `delta` (the values are
+	// upserts and delete records, other keys being left untouched).
+	Mode string `json:"mode,omitempty"`
+
+	// Force makes a DoDelete sync ignore the server's limits on the number of deleted keys.
+	Force bool `json:"force,omitempty"`
+
+	// Headers are added to the produced messages, if the server is configured to (the
+	// "sync2kafka-" prefix is reserved).
+	Headers map[string]string `json:"headers,omitempty"`
+
 	// Token for authn
 	Token string `json:"token"`
 
 	// Topic target topic if not default
 	Topic string `json:"topic"`
+
+	// Handshake requests the server to answer the init object with a SyncResult before reading data
+	// (set by the client unless DisableHandshake).
+	Handshake bool `json:"handshake,omitempty"`
+
+	// WaitForLock makes the server queue the sync if the topic is locked instead of rejecting it.
+	WaitForLock bool `json:"waitForLock,omitempty"`
+
+	// WaitForLockTimeout is the maximum wait for the lock, in seconds (0 means the server's maximum).
+	WaitForLockTimeout int `json:"waitForLockTimeout,omitempty"`
+
+	// DryRun computes the changes without sending anything to Kafka.
+	DryRun bool `json:"dryRun,omitempty"`
+
+	// DryRunSampleSize is the maximum number of changed keys returned by a dry run.
+	DryRunSampleSize int `json:"dryRunSampleSize,omitempty"`
+
+	// Compression is the comma separated list of accepted codecs (gzip, zstd, snappy or lz4), by
+	// order of preference. It requires the handshake, whose response gives the chosen codec, if any.
+	Compression string `json:"compression,omitempty"`
 }
 
 type SyncResult struct {
 	OK bool `json:"ok"`
+
+	// ConnectionID is the server's identifier of the connection (and of the sync)
+	ConnectionID string `json:"connectionId,omitempty"`
+
+	// Compression is the codec of the rest of the stream, in both directions (handshake only)
+	Compression string `json:"compression,omitempty"`
+
+	// ErrorCode identifies the failure (see ErrorCode* constants), if any
+	ErrorCode string `json:"errorCode,omitempty"`
+
+	// Error is the reason of the failure, if any
+	Error string `json:"error,omitempty"`
+
+	// Stats of the sync, if it started
+	Stats *SyncStats `json:"stats,omitempty"`
+
+	// DryRun is the result of a dry run sync
+	DryRun *DryRunResult `json:"dryRun,omitempty"`
+}
+
+// SyncStats are the statistics of a sync. Durations are in nanoseconds.
+type SyncStats struct {
+	// ItemsRead is the number of key/values received from the client
+	ItemsRead int64 `json:"itemsRead"`
+
+	// Diff statistics
+	Created   uint64 `json:"created"`
+	Modified  uint64 `json:"modified"`
+	Deleted   uint64 `json:"deleted"`
+	Unchanged uint64 `json:"unchanged"`
+
+	// Count is the number of active values after the sync (full mode only)
+	Count uint64 `json:"count"`
+
+	// Producer statistics (-1 if not tracked)
+	Sent      uint64 `json:"sent"`
+	Successes int64  `json:"successes"`
+	Errors    int64  `json:"errors"`
+
+	// Performance statistics
+	MessagesInTopic   uint64        `json:"messagesInTopic"`
+	ReadTopicDuration time.Duration `json:"readTopicDuration"`
+	SyncDuration      time.Duration `json:"syncDuration"`
+	TotalDuration     time.Duration `json:"totalDuration"`
+}
+
+// DryRunResult holds the details of a dry run sync, the counts being in the stats.
+type DryRunResult struct {
+	// Sample of the changed keys
+	Sample []ChangedKey `json:"sample,omitempty"`
+
+	// Warning is why the sync would fail, if it would (ie: exceeding the deletion limits)
+	Warning string `json:"warning,omitempty"`
+}
+
+// ChangedKey is a key that would be changed by a sync.
+type ChangedKey struct {
+	// Change is `created`, `modified` or `deleted`
+	Change string `json:"change"`
+
+	// Key is the raw key for the `json` format, the base64 encoded key for the `binary` format.
+	Key json.RawMessage `json:"key"`
 }
 
 type JsonKV struct {
 	Key           *json.RawMessage `json:"k"`
 	Value         *json.RawMessage `json:"v"`
 	EndOfTransfer bool             `json:"EOT"`
+
+	// Delete deletes the key (requires the delete permission, and DoDelete in delta mode); Value is ignored.
+	Delete bool `json:"delete,omitempty"`
 }
 
 type BinaryKV struct {
 	Key           []byte `json:"k"`
 	Value         []byte `json:"v"`
 	EndOfTransfer bool   `json:"EOT"`
+
+	// Delete deletes the key (requires the delete permission, and DoDelete in delta mode); Value is ignored.
+	Delete bool `json:"delete,omitempty"`
 }
@@ -0,0 +1,105 @@
+package client
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/golang/snappy"
+	"github.com/klauspost/compress/zstd"
+	"github.com/pierrec/lz4"
+)
+
+// CompressionWriter is a compressed stream writer. Flush must be called before waiting for the peer.
+type CompressionWriter interface {
+	io.WriteCloser
+	Flush() error
+}
+
+type compressionCodec struct {
+	reader func(r io.Reader) (io.Reader, error)
+	writer func(w io.Writer) (CompressionWriter, error)
+}
+
+var compressionCodecs = map[string]compressionCodec{
+	"gzip": {
+		reader: func(r io.Reader) (io.Reader, error) { return gzip.NewReader(r) },
+		writer: func(w io.Writer) (CompressionWriter, error) { return gzip.NewWriter(w), nil },
+	},
+	"zstd": {
+		reader: func(r io.Reader) (io.Reader, error) { return zstd.NewReader(r, zstd.WithDecoderConcurrency(1)) },
+		writer: func(w io.Writer) (CompressionWriter, error) { return zstd.NewWriter(w) },
+	},
+	"snappy": {
+		reader: func(r io.Reader) (io.Reader, error) { return snappy.NewReader(r), nil },
+		writer: func(w io.Writer) (CompressionWriter, error) { return snappy.NewBufferedWriter(w), nil },
+	},
+	"lz4": {
+		reader: func(r io.Reader) (io.Reader, error) { return lz4.NewReader(r), nil },
+		writer: func(w io.Writer) (CompressionWriter, error) { return lz4.NewWriter(w), nil },
+	},
+}
+
+// NegotiateCompression returns the first supported codec of a comma separated list, or "" if none.
+func NegotiateCompression(codecs string) string {
+	for _, codec := range strings.Split(codecs, ",") {
+		codec = strings.TrimSpace(codec)
+		if _, ok := compressionCodecs[codec]; ok {
+			return codec
+		}
+	}
+	return ""
+}
+
+// NewCompressionWriter returns a writer compressing to w with the given codec.
+func NewCompressionWriter(codec string, w io.Writer) (CompressionWriter, error) {
+	c, ok := compressionCodecs[codec]
+	if !ok {
+		return nil, fmt.Errorf("unknown compression %q", codec)
+	}
+	return c.writer(w)
+}
+
+// NewCompressionReader returns a reader decompressing, with the given codec, what follows
+// the last JSON object read by dec (and its trailing newline) on r.
+//
+// The decompressor is created on the first read, as some codecs read a header first and the
+// peer may be waiting for us before sending anything.
+func NewCompressionReader(codec string, dec *json.Decoder, r io.Reader) (io.Reader, error) {
+	c, ok := compressionCodecs[codec]
+	if !ok {
+		return nil, fmt.Errorf("unknown compression %q", codec)
+	}
+
+	return &lazyReader{open: func() (io.Reader, error) {
+		br := bufio.NewReader(io.MultiReader(dec.Buffered(), r))
+
+		// no codec's stream starts with a newline
+		if err := SkipInitNewline(br); err != nil {
+			return nil, err
+		}
+
+		return c.reader(br)
+	}}, nil
+}
+
+type lazyReader struct {
+	open func() (io.Reader, error)
+	r    io.Reader
+	err  error
+}
+
+func (l *lazyReader) Read(p []byte) (n int, err error) {
+	if l.r == nil && l.err == nil {
+		l.r, l.err = l.open()
+	}
+
+	if l.err != nil {
+		return 0, l.err
+	}
+
+	return l.r.Read(p)
+}
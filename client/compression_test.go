@@ -0,0 +1,121 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"testing"
+	"time"
+)
+
+func TestNegotiateCompression(t *testing.T) {
+	for _, tc := range []struct {
+		codecs, expected string
+	}{
+		{"", ""},
+		{"gzip", "gzip"},
+		{"zstd,gzip", "zstd"},
+		{" snappy , lz4", "snappy"},
+		{"brotli,lz4", "lz4"},
+		{"brotli", ""},
+		{"GZIP", ""},
+		{",,gzip", "gzip"},
+	} {
+		if codec := NegotiateCompression(tc.codecs); codec != tc.expected {
+			t.Errorf("%q: got %q, expected %q", tc.codecs, codec, tc.expected)
+		}
+	}
+}
+
+func TestUnknownCompression(t *testing.T) {
+	if _, err := NewCompressionWriter("brotli", io.Discard); err == nil {
+		t.Error("writer: expected an error")
+	}
+	if _, err := NewCompressionReader("brotli", json.NewDecoder(&bytes.Buffer{}), &bytes.Buffer{}); err == nil {
+		t.Error("reader: expected an error")
+	}
+}
+
+func TestCompressionRoundTrip(t *testing.T) {
+	data := bytes.Repeat([]byte(`{"k":"a key","v":"a value"}`+"\n"), 1000)
+
+	for codec := range compressionCodecs {
+		// the stream follows a JSON object, as after the handshake
+		buf := &bytes.Buffer{}
+		json.NewEncoder(buf).Encode(SyncInitInfo{Format: "json", Compression: codec})
+
+		w, err := NewCompressionWriter(codec, buf)
+		if err != nil {
+			t.Fatalf("%s: %v", codec, err)
+		}
+		w.Write(data)
+		if err = w.Close(); err != nil {
+			t.Fatalf("%s: close failed: %v", codec, err)
+		}
+
+		dec := json.NewDecoder(buf)
+		init := SyncInitInfo{}
+		if err = dec.Decode(&init); err != nil || init.Compression != codec {
+			t.Fatalf("%s: failed to decode the init object: %v", codec, err)
+		}
+
+		r, err := NewCompressionReader(codec, dec, buf)
+		if err != nil {
+			t.Fatalf("%s: %v", codec, err)
+		}
+
+		result, err := io.ReadAll(r)
+		if err != nil {
+			t.Fatalf("%s: read failed: %v", codec, err)
+		}
+
+		if !bytes.Equal(result, data) {
+			t.Errorf("%s: got %d bytes, expected %d", codec, len(result), len(data))
+		}
+	}
+}
+
+func TestCompressionFlush(t *testing.T) {
+	msg := []byte(`{"EOT":true}` + "\n")
+
+	for codec := range compressionCodecs {
+		pr, pw := io.Pipe()
+
+		w, err := NewCompressionWriter(codec, pw)
+		if err != nil {
+			t.Fatalf("%s: %v", codec, err)
+		}
+
+		r, err := NewCompressionReader(codec, json.NewDecoder(&bytes.Buffer{}), pr)
+		if err != nil {
+			t.Fatalf("%s: %v", codec, err)
+		}
+
+		// the peer must get the message without the stream being closed
+		read := make(chan error, 1)
+		go func() {
+			ba := make([]byte, len(msg))
+			_, err := io.ReadFull(r, ba)
+			if err == nil && !bytes.Equal(ba, msg) {
+				err = io.ErrUnexpectedEOF
+			}
+			read <- err
+		}()
+
+		w.Write(msg)
+		if err = w.Flush(); err != nil {
+			t.Fatalf("%s: flush failed: %v", codec, err)
+		}
+
+		select {
+		case err = <-read:
+			if err != nil {
+				t.Errorf("%s: read failed: %v", codec, err)
+			}
+		case <-time.After(5 * time.Second):
+			t.Errorf("%s: flushed data not readable", codec)
+		}
+
+		pw.Close()
+	}
+}
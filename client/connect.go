@@ -1,14 +1,16 @@
 package client
 
 import (
+	"bufio"
 	"context"
 	"crypto/tls"
 	"crypto/x509"
 	"encoding/json"
 	"errors"
-	"fmt"
+	"io"
 	"log"
 	"net"
+	"time"
 )
 
 type sync2KafkaClient struct {
@@ -18,10 +20,13 @@ type sync2KafkaClient struct {
 	caCert             string
 	target             string
 	conn               net.Conn
+	out                io.Writer
+	compressor         CompressionWriter
 	err                error
 	enc                *json.Encoder
 	dec                *json.Decoder
 	syncInit           *SyncInitInfo
+	connectionID       string
 }
 
 // BinarySync2KafkaClient communicates with sync2kafka with binary encoded messages
@@ -33,6 +38,12 @@ type JsonSync2KafkaClient struct {
 	sync2KafkaClient
 }
 
+// RawSync2KafkaClient communicates with sync2kafka with length-prefixed raw frames (see WriteRawKV)
+type RawSync2KafkaClient struct {
+	sync2KafkaClient
+	w *bufio.Writer
+}
+
 // NewBinary creates a new binary client for sync2kafaka server  (uses []byte key value messages for input)
 func NewBinary(config *SyncInitInfo, target string, insecureSkipVerify, useTls bool, caCert string) (client *BinarySync2KafkaClient) {
 	config.Format = "binary"
@@ -49,6 +60,14 @@ func NewJson(config *SyncInitInfo, target string, insecureSkipVerify, useTls boo
 	}
 }
 
+// NewRaw creates a new raw client for sync2kafka server (uses []byte key value messages for input, without JSON encoding)
+func NewRaw(config *SyncInitInfo, target string, insecureSkipVerify, useTls bool, caCert string) (client *RawSync2KafkaClient) {
+	config.Format = "raw"
+	return &RawSync2KafkaClient{
+		sync2KafkaClient: *newSync2KafkaClient(useTls, insecureSkipVerify, caCert, target, config),
+	}
+}
+
 func newSync2KafkaClient(useTls bool, insecureSkipVerify bool, caCert string, target string, config *SyncInitInfo) *sync2KafkaClient {
 	return &sync2KafkaClient{
 		useTLS:             useTls,
@@ -84,6 +103,7 @@ func (c *sync2KafkaClient) Connect(ctx context.Context) (err error) {
 		}
 		c.conn = conn
 	}
+	c.out = c.conn
 	c.enc = json.NewEncoder(c.conn)
 	c.dec = json.NewDecoder(c.conn)
 	return
@@ -119,16 +139,96 @@ func genTLSConf(c *sync2KafkaClient) (config *tls.Config) {
 
 }
 
-// StartTransfer starts a data transfert session. Endtransfer() must be called after transferring all data
+// HandshakeTimeout is the maximum wait for the server's answer to the init object, on top of the
+// WaitForLockTimeout (no limit when waiting for the lock with the server's maximum).
+var HandshakeTimeout = time.Minute
+
+// DisableHandshake stops requesting the handshake by default, for servers not supporting it (they
+// don't answer the init object). It's still requested by SyncInitInfo.Handshake or Compression.
+var DisableHandshake = false
+
+// StartTransfer starts a data transfert session. Endtransfer() must be called after transferring all data.
+// With the handshake (the default, see DisableHandshake), server rejections are returned as *ServerError;
+// otherwise they are only known at EndTransfer(), if the connection isn't reset before.
 func (c *sync2KafkaClient) StartTransfer() (err error) {
 	// initialize transfer
+	if !DisableHandshake || len(c.syncInit.Compression) != 0 {
+		c.syncInit.Handshake = true
+	}
 	if err = c.enc.Encode(c.syncInit); err != nil {
 		return errors.New("sync2KafkaClient system init request error" + err.Error())
 	}
+
+	if !c.syncInit.Handshake {
+		c.isTransfering = true
+		return
+	}
+
+	if deadline, ok := c.handshakeDeadline(); ok {
+		c.conn.SetReadDeadline(deadline)
+		defer c.conn.SetReadDeadline(time.Time{})
+	}
+
+	ack := SyncResult{}
+	if err = c.dec.Decode(&ack); err != nil {
+		return errors.New("sync2KafkaClient system init response error " + err.Error())
+	}
+	c.connectionID = ack.ConnectionID
+	if err = ack.Err(); err != nil {
+		return
+	}
+
+	// the server chose a codec from syncInit.Compression (none for older servers)
+	if len(ack.Compression) != 0 {
+		if err = c.startCompression(ack.Compression); err != nil {
+			return errors.New("sync2KafkaClient compression error " + err.Error())
+		}
+	}
+
 	c.isTransfering = true
 	return
 }
 
+func (c *sync2KafkaClient) handshakeDeadline() (deadline time.Time, ok bool) {
+	timeout := HandshakeTimeout
+	if c.syncInit.WaitForLock {
+		if c.syncInit.WaitForLockTimeout <= 0 {
+			return
+		}
+		timeout += time.Duration(c.syncInit.WaitForLockTimeout) * time.Second
+	}
+	return time.Now().Add(timeout), true
+}
+
+func (c *sync2KafkaClient) startCompression(codec string) (err error) {
+	r, err := NewCompressionReader(codec, c.dec, c.conn)
+	if err != nil {
+		return
+	}
+
+	if c.compressor, err = NewCompressionWriter(codec, c.conn); err != nil {
+		return
+	}
+
+	c.out = c.compressor
+	c.enc = json.NewEncoder(c.out)
+	c.dec = json.NewDecoder(r)
+	return
+}
+
+// flush sends the buffered compressed data, if any.
+func (c *sync2KafkaClient) flush() error {
+	if c.compressor == nil {
+		return nil
+	}
+	return c.compressor.Flush()
+}
+
+// ConnectionID returns the server's identifier of this connection, known after StartTransfer() with the handshake.
+func (c *sync2KafkaClient) ConnectionID() string {
+	return c.connectionID
+}
+
 // SendValue send one value in a Transfer session (after calling StartTransfer() and before calling EndTransfer()
 func (c *BinarySync2KafkaClient) SendValue(kv BinaryKV) (err error) {
 		if err = c.enc.Encode(kv); err != nil {
@@ -137,32 +237,68 @@ func (c *BinarySync2KafkaClient) SendValue(kv BinaryKV) (err error) {
 	return
 }
 
-// EndTransfer ends a data transfer session
-func (c *BinarySync2KafkaClient) EndTransfer() (err error) {
+// SendValue send one value in a Transfer session (after calling StartTransfer() and before calling EndTransfer(); a nil value is sent as null (deleting the key)
+func (c *RawSync2KafkaClient) SendValue(key, value []byte) (err error) {
+	if c.w == nil {
+		c.w = bufio.NewWriter(c.out)
+	}
+
+	if err = WriteRawKV(c.w, key, value); err != nil {
+		return errors.New("sync2KafkaClient request encoding error " + err.Error())
+	}
+	return
+}
+
+// EndTransfer ends a data transfer session and returns the server's result (also on sync failure).
+func (c *RawSync2KafkaClient) EndTransfer() (result *SyncResult, err error) {
+	c.isTransfering = false
+
+	if c.w == nil {
+		c.w = bufio.NewWriter(c.out)
+	}
+
+	if err = WriteRawEOT(c.w); err == nil {
+		err = c.w.Flush()
+	}
+	if err == nil {
+		err = c.flush()
+	}
+	if err != nil {
+		return nil, errors.New("sync2KafkaClient EndOfTransfer request error " + err.Error())
+	}
+
+	return c.readResult()
+}
+
+// EndTransfer ends a data transfer session and returns the server's result (also on sync failure).
+func (c *BinarySync2KafkaClient) EndTransfer() (result *SyncResult, err error) {
 	return c.endTransfer(BinaryKV{EndOfTransfer: true})
 }
 
-// EndTransfer ends a data transfer session
-func (c *JsonSync2KafkaClient) EndTransfer() (err error) {
+// EndTransfer ends a data transfer session and returns the server's result (also on sync failure).
+func (c *JsonSync2KafkaClient) EndTransfer() (result *SyncResult, err error) {
 	return c.endTransfer(JsonKV{EndOfTransfer: true})
 }
 
-func (c *sync2KafkaClient) endTransfer(eof interface {}) (err error) {
+func (c *sync2KafkaClient) endTransfer(eof interface {}) (result *SyncResult, err error) {
 	c.isTransfering = false
 
 	// end transfer
-	if err = c.enc.Encode(eof); err != nil {
-		return errors.New("sync2KafkaClient EndOfTransfer request error " + err.Error())
+	if err = c.enc.Encode(eof); err == nil {
+		err = c.flush()
 	}
-	result := SyncResult{}
-	if err = c.dec.Decode(&result); err != nil {
-		return errors.New("sync2KafkaClient EndOfTransfer response error " + err.Error())
-	}
-	if !result.OK {
-		return fmt.Errorf("sync2KafkaClient result from sync2kafka server is not ok : %v", result)
+	if err != nil {
+		return nil, errors.New("sync2KafkaClient EndOfTransfer request error " + err.Error())
 	}
+	return c.readResult()
+}
 
-	return
+func (c *sync2KafkaClient) readResult() (result *SyncResult, err error) {
+	result = &SyncResult{}
+	if err = c.dec.Decode(result); err != nil {
+		return nil, errors.New("sync2KafkaClient EndOfTransfer response error " + err.Error())
+	}
+	return result, result.Err()
 }
 
 
@@ -171,16 +307,26 @@ func (c *JsonSync2KafkaClient) Close() error {
 	if c.isTransfering {
 		c.EndTransfer()
 	}
-	return c.conn.Close()
+	return c.sync2KafkaClient.Close()
+}
+
+func (c *RawSync2KafkaClient) Close() error {
+	if c.isTransfering {
+		c.EndTransfer()
+	}
+	return c.sync2KafkaClient.Close()
 }
 
 func (c *BinarySync2KafkaClient) Close() error {
 	if c.isTransfering {
 		c.EndTransfer()
 	}
-	return c.conn.Close()
+	return c.sync2KafkaClient.Close()
 }
 
 func (c *sync2KafkaClient) Close() error {
+	if c.compressor != nil {
+		c.compressor.Close()
+	}
 	return c.conn.Close()
 }
\ No newline at end of file
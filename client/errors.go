@@ -0,0 +1,95 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Error codes reported by the server in SyncResult.ErrorCode
+const (
+	ErrorCodeBadRequest        = "bad-request"
+	ErrorCodeUnauthorized      = "unauthorized"
+	ErrorCodeForbidden         = "forbidden"
+	ErrorCodeTopicNotAllowed   = "topic-not-allowed"
+	ErrorCodeTopicLocked       = "topic-locked"
+	ErrorCodeTopicNotFound     = "topic-not-found"
+	ErrorCodeTopicNotCompacted = "topic-not-compacted"
+	ErrorCodeTooManyDeletes    = "too-many-deletes"
+	ErrorCodeUnknownFormat     = "unknown-format"
+	ErrorCodeReadFailed        = "read-failed"
+	ErrorCodeSyncFailed        = "sync-failed"
+	ErrorCodeCancelled         = "cancelled"
+	ErrorCodeInternal          = "internal"
+)
+
+// Errors reported by the server, to be checked with errors.Is
+var (
+	ErrBadRequest        = errors.New("bad request")
+	ErrUnauthorized      = errors.New("unauthorized")
+	ErrForbidden         = errors.New("forbidden")
+	ErrTopicNotAllowed   = errors.New("topic not allowed")
+	ErrTopicLocked       = errors.New("topic locked")
+	ErrTopicNotFound     = errors.New("topic not found")
+	ErrTopicNotCompacted = errors.New("topic not compacted")
+	ErrTooManyDeletes    = errors.New("too many deletes")
+	ErrUnknownFormat     = errors.New("unknown format")
+	ErrReadFailed        = errors.New("read failed")
+	ErrSyncFailed        = errors.New("sync failed")
+	ErrCancelled         = errors.New("sync cancelled")
+	ErrInternal          = errors.New("internal server error")
+)
+
+var codeErrors = map[string]error{
+	ErrorCodeBadRequest:        ErrBadRequest,
+	ErrorCodeUnauthorized:      ErrUnauthorized,
+	ErrorCodeForbidden:         ErrForbidden,
+	ErrorCodeTopicNotAllowed:   ErrTopicNotAllowed,
+	ErrorCodeTopicLocked:       ErrTopicLocked,
+	ErrorCodeTopicNotFound:     ErrTopicNotFound,
+	ErrorCodeTopicNotCompacted: ErrTopicNotCompacted,
+	ErrorCodeTooManyDeletes:    ErrTooManyDeletes,
+	ErrorCodeUnknownFormat:     ErrUnknownFormat,
+	ErrorCodeReadFailed:        ErrReadFailed,
+	ErrorCodeSyncFailed:        ErrSyncFailed,
+	ErrorCodeCancelled:         ErrCancelled,
+	ErrorCodeInternal:          ErrInternal,
+}
+
+// ServerError is a failure reported by the server.
+type ServerError struct {
+	Code    string
+	Message string
+}
+
+func (e *ServerError) Error() string {
+	return fmt.Sprintf("sync2kafka server error %s: %s", e.Code, e.Message)
+}
+
+// Unwrap returns the Err* value matching the error's code, if known.
+func (e *ServerError) Unwrap() error {
+	return codeErrors[e.Code]
+}
+
+// Temporary returns true if retrying the same sync later may succeed.
+func (e *ServerError) Temporary() bool {
+	switch e.Code {
+	case ErrorCodeTopicLocked, ErrorCodeSyncFailed, ErrorCodeInternal:
+		return true
+	}
+	return false
+}
+
+// Err returns the error described by this result, nil if it's OK.
+func (r SyncResult) Err() error {
+	if r.OK {
+		return nil
+	}
+
+	code := r.ErrorCode
+	if len(code) == 0 {
+		// server not reporting codes
+		code = ErrorCodeSyncFailed
+	}
+
+	return &ServerError{Code: code, Message: r.Error}
+}
@@ -0,0 +1,153 @@
+package client
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+// The raw format sends the values as length-prefixed frames after the init object (and its
+// trailing newline, as written by json.Encoder):
+//
+//	key length (uint32, big endian) | key | value length (uint32, big endian) | value
+//
+// A key length of RawEndOfTransfer ends the transfer, and a value length of RawNullValue
+// is a null value (with no value bytes), deleting the key (see JsonKV.Delete).
+const (
+	RawEndOfTransfer uint32 = 0xFFFFFFFF
+	RawNullValue     uint32 = 0xFFFFFFFF
+
+	// RawMaxSize is the maximum size of a key or a value
+	RawMaxSize = 64 << 20
+
+	// larger frames are read in a growing buffer, so memory follows the data actually received
+	rawDirectReadSize = 64 << 10
+)
+
+// WriteRawKV writes a key/value frame.
+func WriteRawKV(w io.Writer, key, value []byte) (err error) {
+	if len(key) > RawMaxSize || len(value) > RawMaxSize {
+		return fmt.Errorf("key or value too large (max %d bytes)", RawMaxSize)
+	}
+
+	if err = writeRawBytes(w, key); err != nil {
+		return
+	}
+
+	if value == nil {
+		return writeRawLength(w, RawNullValue)
+	}
+
+	return writeRawBytes(w, value)
+}
+
+// WriteRawEOT writes the end of transfer marker.
+func WriteRawEOT(w io.Writer) error {
+	return writeRawLength(w, RawEndOfTransfer)
+}
+
+func writeRawLength(w io.Writer, l uint32) error {
+	buf := [4]byte{}
+	binary.BigEndian.PutUint32(buf[:], l)
+
+	_, err := w.Write(buf[:])
+	return err
+}
+
+func writeRawBytes(w io.Writer, ba []byte) (err error) {
+	if err = writeRawLength(w, uint32(len(ba))); err != nil {
+		return
+	}
+
+	_, err = w.Write(ba)
+	return
+}
+
+// ReadRawKV reads a key/value frame. It returns io.EOF only if the stream ends before a frame.
+func ReadRawKV(r *bufio.Reader) (key, value []byte, eot bool, err error) {
+	keyLen, err := readRawLength(r)
+	if err != nil {
+		return
+	}
+
+	if keyLen == RawEndOfTransfer {
+		eot = true
+		return
+	}
+
+	if key, err = readRawBytes(r, keyLen); err != nil {
+		return
+	}
+
+	valueLen, err := readRawLength(r)
+	if err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return
+	}
+
+	if valueLen == RawNullValue {
+		return
+	}
+
+	value, err = readRawBytes(r, valueLen)
+	return
+}
+
+func readRawLength(r *bufio.Reader) (l uint32, err error) {
+	buf := [4]byte{}
+
+	// io.EOF if nothing was read, io.ErrUnexpectedEOF if partially read
+	if _, err = io.ReadFull(r, buf[:]); err != nil {
+		return
+	}
+
+	l = binary.BigEndian.Uint32(buf[:])
+	return
+}
+
+func readRawBytes(r *bufio.Reader, l uint32) (ba []byte, err error) {
+	if l > RawMaxSize {
+		return nil, fmt.Errorf("frame too large: %d bytes (max %d)", l, RawMaxSize)
+	}
+
+	if l <= rawDirectReadSize {
+		ba = make([]byte, l)
+		if _, err = io.ReadFull(r, ba); err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return
+	}
+
+	buf := &bytes.Buffer{}
+	if _, err = io.CopyN(buf, r, int64(l)); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return
+	}
+
+	return buf.Bytes(), nil
+}
+
+// SkipInitNewline skips the newline following the init object, if any.
+//
+// This is unambiguous as a frame starts with a length whose first byte is never '\n'
+// (lengths are at most RawMaxSize, or the end of transfer marker).
+func SkipInitNewline(r *bufio.Reader) error {
+	b, err := r.Peek(1)
+	if err != nil {
+		if err == io.EOF {
+			return nil
+		}
+		return err
+	}
+
+	if b[0] == '\n' {
+		r.Discard(1)
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+package client
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"io"
+	"testing"
+)
+
+func TestRawRoundTrip(t *testing.T) {
+	large := bytes.Repeat([]byte("0123456789"), rawDirectReadSize/5)
+
+	for _, tc := range []struct {
+		name       string
+		key, value []byte
+	}{
+		{"simple", []byte("key"), []byte("value")},
+		{"empty value", []byte("key"), []byte{}},
+		{"null value", []byte("key"), nil},
+		{"empty key", []byte{}, []byte("value")},
+		{"large value", []byte("key"), large},
+		{"large key", large, []byte("value")},
+	} {
+		buf := &bytes.Buffer{}
+		if err := WriteRawKV(buf, tc.key, tc.value); err != nil {
+			t.Fatalf("%s: write failed: %v", tc.name, err)
+		}
+		if err := WriteRawEOT(buf); err != nil {
+			t.Fatalf("%s: write EOT failed: %v", tc.name, err)
+		}
+
+		r := bufio.NewReader(buf)
+
+		key, value, eot, err := ReadRawKV(r)
+		if err != nil || eot {
+			t.Fatalf("%s: read failed: eot=%v err=%v", tc.name, eot, err)
+		}
+
+		if !bytes.Equal(key, tc.key) {
+			t.Errorf("%s: wrong key: %d bytes instead of %d", tc.name, len(key), len(tc.key))
+		}
+		if !bytes.Equal(value, tc.value) || (value == nil) != (tc.value == nil) {
+			t.Errorf("%s: wrong value: %d bytes (nil: %v) instead of %d (nil: %v)",
+				tc.name, len(value), value == nil, len(tc.value), tc.value == nil)
+		}
+
+		if _, _, eot, err = ReadRawKV(r); err != nil || !eot {
+			t.Errorf("%s: expected EOT: eot=%v err=%v", tc.name, eot, err)
+		}
+
+		if _, _, _, err = ReadRawKV(r); err != io.EOF {
+			t.Errorf("%s: expected io.EOF after EOT, got %v", tc.name, err)
+		}
+	}
+}
+
+func TestRawReadErrors(t *testing.T) {
+	length := func(l uint32) []byte {
+		ba := make([]byte, 4)
+		binary.BigEndian.PutUint32(ba, l)
+		return ba
+	}
+
+	concat := func(parts ...[]byte) []byte {
+		return bytes.Join(parts, nil)
+	}
+
+	for _, tc := range []struct {
+		name  string
+		input []byte
+		err   error // nil for any error
+	}{
+		{"partial key length", []byte{0, 0}, io.ErrUnexpectedEOF},
+		{"truncated key", concat(length(3), []byte("k")), io.ErrUnexpectedEOF},
+		{"missing value length", concat(length(1), []byte("k")), io.ErrUnexpectedEOF},
+		{"truncated value", concat(length(1), []byte("k"), length(5), []byte("v")), io.ErrUnexpectedEOF},
+		{"truncated large value", concat(length(1), []byte("k"), length(rawDirectReadSize+1), []byte("v")), io.ErrUnexpectedEOF},
+		{"key too large", length(RawMaxSize + 1), nil},
+		{"value too large", concat(length(1), []byte("k"), length(RawMaxSize+1)), nil},
+	} {
+		_, _, _, err := ReadRawKV(bufio.NewReader(bytes.NewReader(tc.input)))
+		if err == nil || (tc.err != nil && err != tc.err) {
+			t.Errorf("%s: got error %v, expected %v", tc.name, err, tc.err)
+		}
+	}
+}
+
+func TestRawWriteTooLarge(t *testing.T) {
+	if err := WriteRawKV(io.Discard, make([]byte, RawMaxSize+1), nil); err == nil {
+		t.Error("expected an error")
+	}
+}
+
+func TestSkipInitNewline(t *testing.T) {
+	for _, input := range [][]byte{
+		{},
+		{'\n'},
+		{'\n', 0xFF, 0xFF, 0xFF, 0xFF},
+		{0xFF, 0xFF, 0xFF, 0xFF},
+	} {
+		r := bufio.NewReader(bytes.NewReader(input))
+		if err := SkipInitNewline(r); err != nil {
+			t.Fatalf("%v: %v", input, err)
+		}
+
+		_, _, eot, err := ReadRawKV(r)
+		if len(input) <= 1 {
+			if err != io.EOF {
+				t.Errorf("%v: expected io.EOF, got %v", input, err)
+			}
+			continue
+		}
+
+		if err != nil || !eot {
+			t.Errorf("%v: expected EOT: eot=%v err=%v", input, eot, err)
+		}
+	}
+}
@@ -27,6 +27,8 @@ var (
 	server  = flag.String("server", ":9084", "sync2kafka server url")
 	topic             = flag.String("topic", "sync2kafka", "destination topic")
 	sep             = flag.String("separator", " ", "key/value separator (default is space)")
+	compression     = flag.String("compression", "", "accepted compression codecs, by order of preference (gzip, zstd, snappy, lz4)")
+	noHandshake     = flag.Bool("no-handshake", false, "don't wait for the server to accept the sync before sending (for servers without the handshake)")
 
 	s2klient *client.BinarySync2KafkaClient
 )
@@ -37,6 +39,8 @@ func main() {
 
 	SetupCloseHandler()
 
+	client.DisableHandshake = *noHandshake
+
 	// read cert
 	var crt string
 	if tlsCertPath != nil {
@@ -52,10 +56,12 @@ func main() {
 		DoDelete: false,
 		Token:    *token,
 		Topic:    *topic,
+		Compression: *compression,
 	}, *server, *skipVerify, *useTls, crt)
 
-	ctx, _ := context.WithTimeout(context.Background(), 2*time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
 	err := s2klient.Connect(ctx)
+	cancel()
 
 	if err != nil {
 		log.Fatal(err)
@@ -85,7 +91,11 @@ func main() {
 		}
 	}
 
-	if err = s2klient.EndTransfer(); err != nil {
+	result, err := s2klient.EndTransfer()
+	if result != nil && result.Stats != nil {
+		printStats(result.Stats)
+	}
+	if err != nil {
 		log.Fatal(err)
 	}
 
@@ -94,6 +104,24 @@ func main() {
 	}
 }
 
+func printStats(stats *client.SyncStats) {
+	log.Printf("sync stats:")
+	log.Printf("- %d items read", stats.ItemsRead)
+	log.Printf("- %d creations, %d modifications, %d deletions, %d unchanged",
+		stats.Created, stats.Modified, stats.Deleted, stats.Unchanged)
+	log.Printf("- %d active values", stats.Count)
+	log.Printf("- %d messages sent", stats.Sent)
+	if stats.Successes >= 0 {
+		log.Printf("- %d send successes", stats.Successes)
+	}
+	if stats.Errors >= 0 {
+		log.Printf("- %d send errors", stats.Errors)
+	}
+	log.Printf("- read:  %s (%d messages)", stats.ReadTopicDuration, stats.MessagesInTopic)
+	log.Printf("- sync:  %s", stats.SyncDuration)
+	log.Printf("- total: %s", stats.TotalDuration)
+}
+
 func SetupCloseHandler() {
 	c := make(chan os.Signal, 2)
 	signal.Notify(c, os.Interrupt, syscall.SIGTERM)
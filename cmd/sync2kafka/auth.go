@@ -0,0 +1,120 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"path"
+
+	yaml "gopkg.in/yaml.v2"
+
+	"github.com/mcluseau/sync2kafka/client"
+)
+
+var authFile = flag.String("auth-file", "", "Token authorization file (YAML); replaces -token if set")
+
+// AuthConfig maps sync tokens to the topics they may write.
+type AuthConfig struct {
+	Tokens []*TokenAuth `yaml:"tokens"`
+}
+
+// TokenAuth is the authorization granted to a token.
+type TokenAuth struct {
+	// Name identifies the token in logs and statuses.
+	Name string `yaml:"name"`
+	// Token is the secret sent by the client.
+	Token string `yaml:"token"`
+	// Topics the token may write to (glob patterns allowed).
+	Topics []string `yaml:"topics"`
+	// AllowDelete allows the token to use DoDelete.
+	AllowDelete bool `yaml:"allowDelete"`
+}
+
+func loadAuthConfig(filePath string) (cfg *AuthConfig, err error) {
+	ba, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		return
+	}
+
+	cfg = &AuthConfig{}
+	if err = yaml.UnmarshalStrict(ba, cfg); err != nil {
+		return
+	}
+
+	err = cfg.validate()
+	return
+}
+
+func (cfg *AuthConfig) validate() error {
+	seen := map[string]bool{}
+
+	for idx, t := range cfg.Tokens {
+		if len(t.Token) == 0 {
+			return fmt.Errorf("token %d (%q): empty token", idx, t.Name)
+		}
+
+		if seen[t.Token] {
+			return fmt.Errorf("token %d (%q): duplicate token", idx, t.Name)
+		}
+		seen[t.Token] = true
+
+		for _, pattern := range t.Topics {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return fmt.Errorf("token %d (%q): invalid topic pattern %q: %v", idx, t.Name, pattern, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (t *TokenAuth) allowsTopic(topic string) bool {
+	for _, pattern := range t.Topics {
+		if ok, _ := path.Match(pattern, topic); ok {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	errBadToken        = &syncError{client.ErrorCodeUnauthorized, "authentication failed: wrong token"}
+	errTopicDenied     = &syncError{client.ErrorCodeForbidden, "token not allowed to write to this topic"}
+	errDeleteForbidden = &syncError{client.ErrorCodeForbidden, "token not allowed to delete"}
+)
+
+// authorize checks that the token may sync to topic, and returns the identity associated with it.
+func authorize(clientToken, topic string, doDelete bool) (identity string, err error) {
+	authConfig := currentAccess().auth
+	if authConfig == nil {
+		// single global token mode
+		if clientToken != *token {
+			return "", errBadToken
+		}
+		return "", nil
+	}
+
+	var auth *TokenAuth
+	for _, t := range authConfig.Tokens {
+		if t.Token == clientToken {
+			auth = t
+			break
+		}
+	}
+
+	if auth == nil {
+		return "", errBadToken
+	}
+
+	identity = auth.Name
+
+	if !auth.allowsTopic(topic) {
+		return identity, errTopicDenied
+	}
+
+	if doDelete && !auth.AllowDelete {
+		return identity, errDeleteForbidden
+	}
+
+	return
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestAuthorize(t *testing.T) {
+	defer access.Store(loadedAccess())
+
+	access.Store(&accessRules{auth: &AuthConfig{Tokens: []*TokenAuth{
+		{Name: "reader", Token: "t1", Topics: []string{"ref.customers"}},
+		{Name: "writer", Token: "t2", Topics: []string{"ref.*", "other"}, AllowDelete: true},
+	}}})
+
+	for _, tc := range []struct {
+		token, topic string
+		doDelete     bool
+		identity     string
+		err          error
+	}{
+		{"t1", "ref.customers", false, "reader", nil},
+		{"t1", "ref.customers", true, "reader", errDeleteForbidden},
+		{"t1", "ref.products", false, "reader", errTopicDenied},
+		{"t2", "ref.products", true, "writer", nil},
+		{"t2", "other", false, "writer", nil},
+		{"t2", "ref.a.b", false, "writer", nil}, // path.Match's * doesn't stop at dots
+		{"t2", "reference", false, "writer", errTopicDenied},
+		{"t3", "ref.customers", false, "", errBadToken},
+		{"", "ref.customers", false, "", errBadToken},
+	} {
+		identity, err := authorize(tc.token, tc.topic, tc.doDelete)
+		if identity != tc.identity || err != tc.err {
+			t.Errorf("%s/%s/%v: got (%q, %v), expected (%q, %v)",
+				tc.token, tc.topic, tc.doDelete, identity, err, tc.identity, tc.err)
+		}
+	}
+}
+
+func TestAuthorizeSingleToken(t *testing.T) {
+	defer access.Store(loadedAccess())
+	defer func(prev string) { *token = prev }(*token)
+
+	access.Store(&accessRules{})
+	*token = "secret"
+
+	for _, tc := range []struct {
+		token string
+		err   error
+	}{
+		{"secret", nil},
+		{"other", errBadToken},
+		{"", errBadToken},
+	} {
+		if _, err := authorize(tc.token, "any", true); err != tc.err {
+			t.Errorf("%q: got %v, expected %v", tc.token, err, tc.err)
+		}
+	}
+}
+
+func TestAuthConfigValidate(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		tokens []*TokenAuth
+		ok     bool
+	}{
+		{"valid", []*TokenAuth{{Token: "a", Topics: []string{"x.*"}}, {Token: "b"}}, true},
+		{"empty token", []*TokenAuth{{Name: "x"}}, false},
+		{"duplicate token", []*TokenAuth{{Token: "a"}, {Token: "a"}}, false},
+		{"invalid pattern", []*TokenAuth{{Token: "a", Topics: []string{"x["}}}, false},
+	} {
+		err := (&AuthConfig{Tokens: tc.tokens}).validate()
+		if (err == nil) != tc.ok {
+			t.Errorf("%s: got error %v", tc.name, err)
+		}
+	}
+}
+
+// loadedAccess returns the current access rules, or empty ones if not loaded.
+func loadedAccess() *accessRules {
+	if rules, ok := access.Load().(*accessRules); ok {
+		return rules
+	}
+	return &accessRules{}
+}
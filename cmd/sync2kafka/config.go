@@ -0,0 +1,279 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"sort"
+	"strings"
+
+	yaml "gopkg.in/yaml.v2"
+
+	"github.com/mcluseau/sync2kafka/client"
+)
+
+var configFile = flag.String("config", "", "Configuration file (YAML); command line flags override its settings")
+
+// Config is the server's configuration file.
+type Config struct {
+	// Settings are command line flags values, by flag name (without the leading dash).
+	Settings map[string]interface{} `yaml:"settings"`
+
+	// Tokens (same as the -auth-file content)
+	AuthConfig `yaml:",inline"`
+
+	// Topics are per-topic settings
+	Topics map[string]*TopicConfig `yaml:"topics"`
+}
+
+// TopicConfig are the settings of a topic.
+type TopicConfig struct {
+	// Tokens are the names of the tokens allowed to sync this topic (any if empty).
+	Tokens []string `yaml:"tokens"`
+
+	// DeletePolicy is `allow` (the default; subject to the token's permission) or `deny`.
+	DeletePolicy string `yaml:"deletePolicy"`
+
+	// Partition is the topic's partition to synchronize. It's the only partitioner: the diff is
+	// computed against a single partition's index, so a key hash partitioner isn't supported.
+	Partition int32 `yaml:"partition"`
+
+	// Formats are the allowed data formats (any if empty), the serialization being the client's
+	// (the values are written as sent).
+	Formats []string `yaml:"formats"`
+
+	// MaxDeletes and MaxDeletePercent override the -max-deletes and -max-delete-percent limits.
+	MaxDeletes       *int     `yaml:"maxDeletes"`
+	MaxDeletePercent *float64 `yaml:"maxDeletePercent"`
+}
+
+var defaultTopicConfig = &TopicConfig{}
+
+func getTopicConfig(topic string) *TopicConfig {
+	if cfg, ok := currentAccess().topics[topic]; ok {
+		return cfg
+	}
+	return defaultTopicConfig
+}
+
+// setupConfig loads the configuration file, if any, and validates the whole configuration.
+func setupConfig() {
+	errs := make([]string, 0)
+	addErr := func(err error) {
+		errs = append(errs, err.Error())
+	}
+
+	if len(*configFile) != 0 {
+		if err := loadConfigSettings(*configFile); err != nil {
+			addErr(err)
+		}
+	}
+
+	for _, err := range validateConfig() {
+		addErr(err)
+	}
+
+	if status := reloadAccess("startup"); !status.OK {
+		errs = append(errs, status.Error)
+	}
+
+	if len(errs) != 0 {
+		log.Fatal("invalid configuration:\n- ", strings.Join(errs, "\n- "))
+	}
+}
+
+// parseConfig reads and validates the configuration file.
+func parseConfig(filePath string) (cfg *Config, errs []string) {
+	ba, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		return nil, []string{err.Error()}
+	}
+
+	cfg = &Config{}
+	if err = yaml.UnmarshalStrict(ba, cfg); err != nil {
+		return nil, []string{err.Error()}
+	}
+
+	if len(cfg.Tokens) != 0 {
+		if err := cfg.AuthConfig.validate(); err != nil {
+			errs = append(errs, "tokens: "+err.Error())
+		}
+	}
+
+	topics := make([]string, 0, len(cfg.Topics))
+	for topic := range cfg.Topics {
+		topics = append(topics, topic)
+	}
+	sort.Strings(topics)
+
+	for _, topic := range topics {
+		if err := cfg.Topics[topic].validate(); err != nil {
+			errs = append(errs, fmt.Sprintf("topics: %s: %v", topic, err))
+		}
+	}
+
+	return
+}
+
+// loadConfigSettings applies the configuration file's settings not given on the command line.
+func loadConfigSettings(filePath string) (err error) {
+	cfg, errs := parseConfig(filePath)
+	if cfg == nil {
+		return fmt.Errorf("%s: %s", filePath, errs[0])
+	}
+
+	setFlags := map[string]bool{}
+	flag.Visit(func(f *flag.Flag) { setFlags[f.Name] = true })
+
+	names := make([]string, 0, len(cfg.Settings))
+	for name := range cfg.Settings {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	errs = make([]string, 0)
+
+	for _, name := range names {
+		if flag.Lookup(name) == nil {
+			errs = append(errs, fmt.Sprintf("settings: unknown setting %q", name))
+			continue
+		}
+
+		if setFlags[name] {
+			continue
+		}
+
+		if err := flag.Set(name, fmt.Sprint(cfg.Settings[name])); err != nil {
+			errs = append(errs, fmt.Sprintf("settings: %s: %v", name, err))
+		}
+	}
+
+	if len(errs) != 0 {
+		return fmt.Errorf("%s:\n  - %s", filePath, strings.Join(errs, "\n  - "))
+	}
+
+	log.Printf("loaded settings from %s", filePath)
+	return
+}
+
+func (tc *TopicConfig) validate() error {
+	switch tc.DeletePolicy {
+	case "", "allow", "deny":
+	default:
+		return fmt.Errorf("invalid delete policy %q", tc.DeletePolicy)
+	}
+
+	if tc.Partition < 0 {
+		return fmt.Errorf("invalid partition %d", tc.Partition)
+	}
+
+	if *createTopics && tc.Partition >= int32(*topicPartitions) {
+		return fmt.Errorf("partition %d is not in created topics (topic-partitions: %d)", tc.Partition, *topicPartitions)
+	}
+
+	for _, format := range tc.Formats {
+		switch format {
+		case "json", "binary", "raw":
+		default:
+			return fmt.Errorf("unknown format %q", format)
+		}
+	}
+
+	if tc.MaxDeletes != nil {
+		if err := validateDeleteLimits(*tc.MaxDeletes, 0); err != nil {
+			return err
+		}
+	}
+
+	if tc.MaxDeletePercent != nil {
+		if err := validateDeleteLimits(0, *tc.MaxDeletePercent); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// validateTokens checks that the topic's tokens exist.
+func (tc *TopicConfig) validateTokens(auth *AuthConfig) error {
+tokensLoop:
+	for _, name := range tc.Tokens {
+		if auth != nil {
+			for _, t := range auth.Tokens {
+				if t.Name == name {
+					continue tokensLoop
+				}
+			}
+		}
+		return fmt.Errorf("unknown token %q", name)
+	}
+
+	return nil
+}
+
+// check verifies that the sync is allowed by the topic's settings.
+func (tc *TopicConfig) check(identity string, init *SyncInitInfo) error {
+	if len(tc.Formats) != 0 && !containsString(tc.Formats, init.Format) {
+		return newSyncError(client.ErrorCodeForbidden, "format %q not allowed on this topic", init.Format)
+	}
+
+	if len(tc.Tokens) != 0 && !containsString(tc.Tokens, identity) {
+		return newSyncError(client.ErrorCodeForbidden, "token not allowed on this topic")
+	}
+
+	if tc.DeletePolicy == "deny" && init.DoDelete && !init.DryRun {
+		return newSyncError(client.ErrorCodeForbidden, "deletions not allowed on this topic")
+	}
+
+	return nil
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// validateConfig checks the settings consistency.
+func validateConfig() (errs []error) {
+	if len(*tlsKeyPath) != 0 && len(*tlsCertPath) == 0 {
+		errs = append(errs, errors.New("tls-cert is required when tls-key is set"))
+	}
+
+	switch *lockBackend {
+	case "memory", "kafka":
+	default:
+		errs = append(errs, fmt.Errorf("unknown lock backend: %q", *lockBackend))
+	}
+
+	if *lockLease <= 0 {
+		errs = append(errs, errors.New("lock-lease must be positive"))
+	}
+
+	if *lockPollInterval <= 0 {
+		errs = append(errs, errors.New("lock-poll-interval must be positive"))
+	}
+
+	if *maxIndexings <= 0 {
+		errs = append(errs, errors.New("parallel-indexers must be positive"))
+	}
+
+	kconf, err := kafkaConfig()
+	if err != nil {
+		errs = append(errs, fmt.Errorf("kafka: %v", err))
+	}
+
+	errs = append(errs, validateTopicAdminConfig(kconf)...)
+	errs = append(errs, validateMessageHeadersConfig(kconf)...)
+
+	if err := validateDeleteLimits(*maxDeletes, *maxDeletePercent); err != nil {
+		errs = append(errs, err)
+	}
+
+	return
+}
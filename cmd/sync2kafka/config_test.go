@@ -0,0 +1,31 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestTopicConfigValidatePartition(t *testing.T) {
+	defer func(prev bool) { *createTopics = prev }(*createTopics)
+	defer func(prev int) { *topicPartitions = prev }(*topicPartitions)
+
+	*topicPartitions = 3
+
+	for _, tc := range []struct {
+		create    bool
+		partition int32
+		ok        bool
+	}{
+		{false, -1, false},
+		{false, 5, true}, // existing topics may have more partitions
+		{true, 0, true},
+		{true, 2, true},
+		{true, 3, false}, // not in created topics
+	} {
+		*createTopics = tc.create
+
+		err := (&TopicConfig{Partition: tc.partition}).validate()
+		if (err == nil) != tc.ok {
+			t.Errorf("create=%v partition=%d: got error %v", tc.create, tc.partition, err)
+		}
+	}
+}
@@ -1,7 +1,6 @@
 package main
 
 import (
-	"net"
 	"sync"
 	"time"
 
@@ -14,13 +13,29 @@ var (
 )
 
 type ConnStatus struct {
+	ID          string
 	Remote      string
+	Identity    string
 	Status      string
 	TargetTopic string
+	DryRun      bool
+	Mode        string
 	ItemsRead   int64
 	SyncStats   *kafkasync.Stats
 	StartTime   time.Time
 	EndTime     time.Time
+
+	// QueuePosition is the position in the topic lock queue while waiting (1 is next)
+	QueuePosition int
+
+	// lockHeld is true once the connection holds its target topic's lock
+	lockHeld bool
+
+	// deleteDenied is why delete records are refused, nil if allowed
+	deleteDenied error
+
+	cancel     chan bool
+	cancelOnce sync.Once
 }
 
 func connStatusCleaner() {
@@ -41,24 +56,53 @@ func connStatusCleaner() {
 		}
 
 		for _, exp := range expired {
-			delete(connStatuses, exp.Remote)
+			delete(connStatuses, exp.ID)
 		}
 
 		connStatusesMutex.Unlock()
 	}
 }
 
-func newConnStatus(conn net.Conn) (cs *ConnStatus) {
+func newConnStatus(remote string) (cs *ConnStatus) {
 	cs = &ConnStatus{
-		Remote:    conn.RemoteAddr().String(),
+		ID:        newULID().String(),
+		Remote:    remote,
 		Status:    "initializing",
 		StartTime: time.Now(),
+		cancel:    make(chan bool),
 	}
 
 	connStatusesMutex.Lock()
 	defer connStatusesMutex.Unlock()
 
-	connStatuses[cs.Remote] = cs
+	connStatuses[cs.ID] = cs
+
+	return
+}
+
+func getConnStatus(id string) *ConnStatus {
+	connStatusesMutex.Lock()
+	defer connStatusesMutex.Unlock()
+
+	return connStatuses[id]
+}
+
+// getTopicConnStatus returns the status of the connection syncing the topic, or else of a
+// connection waiting for its lock, if any.
+func getTopicConnStatus(topic string) (queued *ConnStatus) {
+	connStatusesMutex.Lock()
+	defer connStatusesMutex.Unlock()
+
+	for _, cs := range connStatuses {
+		if cs.TargetTopic != topic || !cs.EndTime.IsZero() {
+			continue
+		}
+
+		if cs.lockHeld {
+			return cs
+		}
+		queued = cs
+	}
 
 	return
 }
@@ -67,3 +111,18 @@ func (cs *ConnStatus) Finished() {
 	cs.Status = "finished"
 	cs.EndTime = time.Now()
 }
+
+// Cancel aborts the connection's sync, if any.
+func (cs *ConnStatus) Cancel() {
+	cs.cancelOnce.Do(func() { close(cs.cancel) })
+}
+
+// Cancelled returns true if the connection's sync was cancelled.
+func (cs *ConnStatus) Cancelled() bool {
+	select {
+	case <-cs.cancel:
+		return true
+	default:
+		return false
+	}
+}
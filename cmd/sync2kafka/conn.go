@@ -3,14 +3,16 @@ package main
 import (
 	"bufio"
 	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
+	"io"
 	"log"
 	"net"
-	"os"
 	"runtime"
-	"sync"
+	"time"
 
+	diff "github.com/mcluseau/go-diff"
 	kafkasync "github.com/mcluseau/kafka-sync"
 
 	"github.com/mcluseau/sync2kafka/client"
@@ -18,10 +20,13 @@ import (
 
 const kvBufferSize = 1000
 
+// rejectedDrainTimeout is the maximum time spent discarding the data of a rejected client (see closeDrained).
+const rejectedDrainTimeout = 10 * time.Second
+
 var (
 	token             = flag.String("token", "", "Require a token to operate")
 	allowAllTopics    = flag.Bool("allow-all-topics", false, "Allow any topic to be synchronized")
-	allowedTopicsFile = flag.String("allowed-topics-file", "", "File containing allowed topics (1 per line; # is comment)")
+	allowedTopicsFile = flag.String("allowed-topics-file", "", "File containing allowed topics (1 per line: name, glob or /regexp/; ! prefix to deny; # is comment)")
 )
 
 type KeyValue = kafkasync.KeyValue
@@ -31,122 +36,216 @@ type SyncResult = client.SyncResult
 type JsonKV = client.JsonKV
 type BinaryKV = client.BinaryKV
 
+// syncError is an error reported to the client with its code.
+type syncError struct {
+	code string
+	msg  string
+}
+
+func newSyncError(code, format string, args ...interface{}) *syncError {
+	return &syncError{code, fmt.Sprintf(format, args...)}
+}
+
+func (e *syncError) Error() string {
+	return e.msg
+}
+
+func errorResult(err error) SyncResult {
+	code := client.ErrorCodeInternal
+	se := &syncError{}
+	if errors.As(err, &se) {
+		code = se.code
+	}
+
+	return SyncResult{OK: false, ErrorCode: code, Error: err.Error()}
+}
+
+var errCancelled = &syncError{client.ErrorCodeCancelled, "sync cancelled"}
+
 func handleConn(conn net.Conn) {
-	logPrefix := fmt.Sprintf("from %v: ", conn.RemoteAddr().String())
+	status := newConnStatus(conn.RemoteAddr().String())
+	logPrefix := fmt.Sprintf("%s from %v: ", status.ID, status.Remote)
 
 	log.Print(logPrefix, "new connection")
-	status := newConnStatus(conn)
 
-	defer func() {
-		log.Print(logPrefix, "closing connection")
-		conn.Close()
-		status.Finished()
+	activeConnections.Inc()
+	defer activeConnections.Dec()
+
+	enc := json.NewEncoder(conn)
+	dec := json.NewDecoder(conn)
+
+	// set once the stream is compressed
+	var compressor client.CompressionWriter
+
+	responded, failed := false, false
+	respond := func(result SyncResult) {
+		responded, failed = true, !result.OK
+		result.ConnectionID = status.ID
+		enc.Encode(result)
+
+		if compressor != nil {
+			compressor.Close()
+		}
+	}
 
+	defer func() {
 		if err := recover(); err != nil {
 			buf := make([]byte, 64*1024)
 			runtime.Stack(buf, false)
 			log.Print(logPrefix, "panic: ", err, "\n", string(buf))
+			if !responded {
+				respond(errorResult(newSyncError(client.ErrorCodeInternal, "internal error")))
+			}
 		}
-	}()
 
-	enc := json.NewEncoder(conn)
-	dec := json.NewDecoder(conn)
+		log.Print(logPrefix, "closing connection")
+		if failed {
+			closeDrained(conn)
+		} else {
+			conn.Close()
+		}
+		status.Finished()
+	}()
 
 	init := &SyncInitInfo{}
 	if err := dec.Decode(init); err != nil {
-		log.Print(logPrefix, "failed to read init object: ", err)
+		err = newSyncError(client.ErrorCodeBadRequest, "failed to read init object: %v", err)
+		log.Print(logPrefix, "rejecting: ", err)
+		respond(errorResult(err))
 		return
 	}
 
-	if init.Token != *token {
-		log.Print(logPrefix, "authentication failed: wrong token")
-		return
+	session := &syncSession{
+		status:    status,
+		init:      init,
+		logPrefix: logPrefix,
+		readKVs:   kvReader(init.Format, dec, conn),
+		interrupt: func() { conn.SetReadDeadline(time.Now()) },
 	}
 
-	topic := *targetTopic
-	if len(init.Topic) != 0 {
-		topic = init.Topic
-	}
+	if init.Handshake {
+		// compression is negotiated by the handshake, so clients fall back to none with older servers
+		compression := client.NegotiateCompression(init.Compression)
+		if len(init.Compression) != 0 && len(compression) == 0 {
+			log.Printf("%sno supported compression in %q, not compressing", logPrefix, init.Compression)
+		}
 
-	if len(topic) == 0 {
-		log.Printf("%srejecting: no topic specified and no default topic", logPrefix)
-		return
+		session.accepted = func() (err error) {
+			err = enc.Encode(SyncResult{OK: true, ConnectionID: status.ID, Compression: compression})
+			if err != nil || len(compression) == 0 {
+				return
+			}
+
+			if compressor, err = client.NewCompressionWriter(compression, conn); err != nil {
+				return
+			}
+			enc = json.NewEncoder(compressor)
+
+			r, err := client.NewCompressionReader(compression, dec, conn)
+			if err != nil {
+				return
+			}
+			session.readKVs = kvReader(init.Format, json.NewDecoder(r), r)
+
+			log.Print(logPrefix, "using compression ", compression)
+			return
+		}
 	}
 
-	if !isTopicAllowed(topic) {
-		log.Printf("%srejecting topic %q", logPrefix, init.Topic)
-		return
-	}
+	respond(session.run())
+}
 
-	if !lockTopic(topic) {
-		log.Printf("%srejecting, topic %q already locked.", logPrefix, topic)
+// closeDrained closes a connection whose client may still be sending (ie: rejected without the
+// handshake). Closing with unread data resets the connection, and the client gets ECONNRESET or
+// EPIPE instead of the result, so the write side is closed first and the input discarded.
+func closeDrained(conn net.Conn) {
+	defer conn.Close()
+
+	cw, ok := conn.(interface{ CloseWrite() error })
+	if !ok || cw.CloseWrite() != nil {
 		return
 	}
-	defer unlockTopic(topic)
 
-	log.Printf("%saccepting topic %q", logPrefix, init.Topic)
-	status.TargetTopic = topic
-	logPrefix += fmt.Sprintf("to topic %q: ", init.Topic)
+	conn.SetReadDeadline(time.Now().Add(rejectedDrainTimeout))
+	io.Copy(io.Discard, conn)
+}
 
-	wg := sync.WaitGroup{}
-	wg.Add(1)
+func resultStats(itemsRead int64, stats *SyncStats) *client.SyncStats {
+	return &client.SyncStats{
+		ItemsRead:         itemsRead,
+		Created:           stats.Created,
+		Modified:          stats.Modified,
+		Deleted:           stats.Deleted,
+		Unchanged:         stats.Unchanged,
+		Count:             stats.Count,
+		Sent:              stats.SendCount,
+		Successes:         stats.SuccessCount,
+		Errors:            stats.ErrorCount,
+		MessagesInTopic:   stats.MessagesInTopic,
+		ReadTopicDuration: stats.ReadTopicDuration,
+		SyncDuration:      stats.SyncDuration,
+		TotalDuration:     stats.TotalDuration,
+	}
+}
 
-	var syncErr error
+func dryRunResult(format string, sample []diff.Change) *client.DryRunResult {
+	res := &client.DryRunResult{}
 
-	kvSource := make(chan KeyValue, kvBufferSize)
+	for _, change := range sample {
+		key := json.RawMessage(change.Key)
+		if format != "json" {
+			key, _ = json.Marshal(change.Key)
+		}
 
-	cancel := make(chan bool, 1)
-	defer close(cancel)
+		var changeType string
+		switch change.Type {
+		case diff.Created:
+			changeType = "created"
+		case diff.Modified:
+			changeType = "modified"
+		case diff.Deleted:
+			changeType = "deleted"
+		}
 
-	go func() {
-		defer wg.Done()
-		status.SyncStats, syncErr = (&syncSpec{
-			Source:      kvSource,
-			TargetTopic: topic,
-			DoDelete:    init.DoDelete,
-			Cancel:      cancel,
-		}).sync()
-	}()
+		res.Sample = append(res.Sample, client.ChangedKey{Change: changeType, Key: key})
+	}
 
-	status.Status = "reading data"
+	return res
+}
 
-	var err error
-	switch init.Format {
+// kvReader returns the reader of values in the given format, nil if unknown.
+// The values are read from dec, or from r after dec's buffered data for non-JSON formats.
+func kvReader(format string, dec *json.Decoder, r io.Reader) func(out chan KeyValue, status *ConnStatus) error {
+	switch format {
 	case "json":
-		err = readJsonKVs(dec, kvSource, status)
+		return func(out chan KeyValue, status *ConnStatus) error { return readJsonKVs(dec, out, status) }
 
 	case "binary":
-		log.Println("read binary")
-		err = readBinaryKVs(dec, kvSource, status)
+		return func(out chan KeyValue, status *ConnStatus) error { return readBinaryKVs(dec, out, status) }
 
-	default:
-		log.Printf("%sunknown mode %q, closing connection", logPrefix, init.Format)
-		return
-	}
+	case "raw":
+		br := bufio.NewReader(io.MultiReader(dec.Buffered(), r))
+		return func(out chan KeyValue, status *ConnStatus) error { return readRawKVs(br, out, status) }
 
-	if err != nil {
-		log.Printf("%sfailed to read values from %v: %v", logPrefix, conn.RemoteAddr(), err)
-		return
+	default:
+		return nil
 	}
+}
 
-	log.Print(logPrefix, "finished reading values")
-	close(kvSource)
-
-	status.Status = "finializing"
-	wg.Wait()
-
-	if status.SyncStats != nil {
-		log.Print(logPrefix, "sync stats:\n", status.SyncStats.LogString())
+// toKV returns the key/value to sync, a nil value being a delete.
+func toKV(status *ConnStatus, key, value []byte, del bool) (KeyValue, error) {
+	if !del {
+		if value == nil {
+			return KeyValue{}, errors.New("missing value")
+		}
+		return KeyValue{Key: key, Value: value}, nil
 	}
 
-	if syncErr != nil {
-		enc.Encode(SyncResult{false})
-
-		log.Print(logPrefix, "sync failed: ", syncErr)
-		return
+	if status.deleteDenied != nil {
+		return KeyValue{}, status.deleteDenied
 	}
 
-	enc.Encode(SyncResult{true})
+	return KeyValue{Key: key}, nil
 }
 
 func readJsonKVs(dec *json.Decoder, out chan KeyValue, status *ConnStatus) error {
@@ -162,9 +261,24 @@ func readJsonKVs(dec *json.Decoder, out chan KeyValue, status *ConnStatus) error
 
 		status.ItemsRead++
 
-		out <- KeyValue{
-			Key:   *obj.Key,
-			Value: *obj.Value,
+		if obj.Key == nil {
+			return errors.New("missing key")
+		}
+
+		var value []byte
+		if obj.Value != nil {
+			value = *obj.Value
+		}
+
+		kv, err := toKV(status, *obj.Key, value, obj.Delete)
+		if err != nil {
+			return err
+		}
+
+		select {
+		case out <- kv:
+		case <-status.cancel:
+			return errCancelled
 		}
 	}
 }
@@ -182,52 +296,60 @@ func readBinaryKVs(dec *json.Decoder, out chan KeyValue, status *ConnStatus) err
 
 		status.ItemsRead++
 
-		out <- KeyValue{
-			Key:   obj.Key,
-			Value: obj.Value,
+		kv, err := toKV(status, obj.Key, obj.Value, obj.Delete)
+		if err != nil {
+			return err
+		}
+
+		select {
+		case out <- kv:
+		case <-status.cancel:
+			return errCancelled
 		}
 	}
 }
 
-func isTopicAllowed(topic string) bool {
-	if *allowAllTopics {
-		return true
+func readRawKVs(r *bufio.Reader, out chan KeyValue, status *ConnStatus) error {
+	if err := client.SkipInitNewline(r); err != nil {
+		return err
 	}
 
-	if len(*allowedTopicsFile) == 0 {
-		return topic == *targetTopic
-	}
+	for {
+		key, value, eot, err := client.ReadRawKV(r)
+		if err != nil {
+			return err
+		}
 
-	// check allowed topics file
-	file, err := os.Open(*allowedTopicsFile)
-	if err != nil {
-		log.Print("failed to open allowed topics file, not allowing: ", err)
-		return false
-	}
+		if eot {
+			return nil
+		}
 
-	defer file.Close()
+		status.ItemsRead++
 
-	scanner := bufio.NewScanner(file)
-	for scanner.Scan() {
-		line := scanner.Text()
-		if len(topic) == 0 {
-			continue
+		kv, err := toKV(status, key, value, value == nil)
+		if err != nil {
+			return err
 		}
 
-		if line[0] == '#' {
-			continue
+		select {
+		case out <- kv:
+		case <-status.cancel:
+			return errCancelled
 		}
+	}
+}
 
-		if line == topic {
-			return true
-		}
+func isTopicAllowed(topic string) bool {
+	allowedTopics := currentAccess().allowedTopics
+
+	if *allowAllTopics {
+		// deny entries still apply
+		return allowedTopics == nil || !allowedTopics.denies(topic)
 	}
 
-	if err := scanner.Err(); err != nil {
-		log.Printf("failed to read allowed topics, not allowing: %v", err)
-		return false
+	if allowedTopics == nil {
+		return topic == *targetTopic
 	}
 
-	// nothing more to allow
-	return false
+	return allowedTopics.allows(topic)
 }
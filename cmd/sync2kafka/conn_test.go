@@ -0,0 +1,66 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"net"
+	"strings"
+	"testing"
+)
+
+func TestReadJsonKVs(t *testing.T) {
+	for _, tc := range []struct {
+		name  string
+		input string
+		ok    bool
+	}{
+		{"value", `{"k":"a","v":1} {"EOT":true}`, true},
+		{"delete", `{"k":"a","delete":true} {"EOT":true}`, true},
+		{"missing key", `{"v":1}`, false},
+		{"missing value", `{"k":"a"}`, false},
+	} {
+		out := make(chan KeyValue, 1)
+		status := &ConnStatus{cancel: make(chan bool)}
+
+		err := readJsonKVs(json.NewDecoder(strings.NewReader(tc.input)), out, status)
+		if (err == nil) != tc.ok {
+			t.Errorf("%s: got error %v", tc.name, err)
+		}
+	}
+}
+
+func TestCloseDrained(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	go func() {
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+
+		// reject without reading anything
+		conn.Write([]byte("rejected\n"))
+		closeDrained(conn)
+	}()
+
+	conn, err := net.Dial("tcp", l.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	// the client sends its data before reading the result
+	if _, err = conn.Write(bytes.Repeat([]byte("data"), 1<<20)); err != nil {
+		t.Fatal("write failed: ", err)
+	}
+
+	line, err := bufio.NewReader(conn).ReadString('\n')
+	if err != nil || line != "rejected\n" {
+		t.Errorf("got %q, %v", line, err)
+	}
+}
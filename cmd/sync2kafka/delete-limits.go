@@ -0,0 +1,70 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/mcluseau/sync2kafka/client"
+)
+
+var (
+	maxDeletes       = flag.Int("max-deletes", 0, "Maximum number of unseen keys a DoDelete sync may delete (0: no limit; syncs can force)")
+	maxDeletePercent = flag.Float64("max-delete-percent", 0, "Maximum percentage of the topic's keys a DoDelete sync may delete (0: no limit; syncs can force)")
+)
+
+// deleteLimits limit the deletion of unseen keys by a sync (0 being no limit).
+type deleteLimits struct {
+	count   int
+	percent float64
+}
+
+// getDeleteLimits returns the topic's limits, defaulting to the global ones.
+func getDeleteLimits(topic string) deleteLimits {
+	l := deleteLimits{*maxDeletes, *maxDeletePercent}
+
+	tc := getTopicConfig(topic)
+	if tc.MaxDeletes != nil {
+		l.count = *tc.MaxDeletes
+	}
+	if tc.MaxDeletePercent != nil {
+		l.percent = *tc.MaxDeletePercent
+	}
+
+	return l
+}
+
+func (l deleteLimits) none() bool {
+	return l.count == 0 && l.percent == 0
+}
+
+// check returns an error if deleting count keys of the total exceeds the limits.
+func (l deleteLimits) check(count, total uint64) error {
+	if l.count > 0 && count > uint64(l.count) ||
+		l.percent > 0 && float64(count)*100 > l.percent*float64(total) {
+		return newSyncError(client.ErrorCodeTooManyDeletes,
+			"the sync would delete %d of the %d keys, exceeding the limit (%s); force the sync to override", count, total, l)
+	}
+	return nil
+}
+
+func (l deleteLimits) String() string {
+	limits := make([]string, 0, 2)
+	if l.count > 0 {
+		limits = append(limits, fmt.Sprintf("%d keys", l.count))
+	}
+	if l.percent > 0 {
+		limits = append(limits, fmt.Sprintf("%g%%", l.percent))
+	}
+	return "max " + strings.Join(limits, " and ")
+}
+
+func validateDeleteLimits(count int, percent float64) error {
+	if count < 0 {
+		return fmt.Errorf("invalid max deletes %d", count)
+	}
+	if percent < 0 || percent > 100 {
+		return fmt.Errorf("invalid max delete percent %g", percent)
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/mcluseau/sync2kafka/client"
+)
+
+func TestDeleteLimitsCheck(t *testing.T) {
+	for _, tc := range []struct {
+		limits       deleteLimits
+		count, total uint64
+		ok           bool
+	}{
+		{deleteLimits{}, 1000, 1000, true},
+		{deleteLimits{count: 10}, 0, 100, true},
+		{deleteLimits{count: 10}, 10, 100, true},
+		{deleteLimits{count: 10}, 11, 100, false},
+		{deleteLimits{percent: 10}, 10, 100, true},
+		{deleteLimits{percent: 10}, 11, 100, false},
+		{deleteLimits{percent: 10}, 0, 0, true},
+		{deleteLimits{percent: 10}, 1, 1, false},
+		{deleteLimits{percent: 100}, 5, 5, true},
+		{deleteLimits{percent: 0.5}, 1, 200, true},
+		{deleteLimits{percent: 0.5}, 2, 200, false},
+		{deleteLimits{count: 10, percent: 50}, 10, 100, true},
+		{deleteLimits{count: 10, percent: 50}, 11, 100, false}, // count exceeded
+		{deleteLimits{count: 10, percent: 50}, 6, 10, false},   // percent exceeded
+	} {
+		err := tc.limits.check(tc.count, tc.total)
+		if (err == nil) != tc.ok {
+			t.Errorf("%v, %d of %d: got error %v", tc.limits, tc.count, tc.total, err)
+			continue
+		}
+
+		if err != nil && !errors.Is(errorResult(err).Err(), client.ErrTooManyDeletes) {
+			t.Errorf("%v, %d of %d: wrong error %v", tc.limits, tc.count, tc.total, err)
+		}
+	}
+}
+
+func TestValidateDeleteLimits(t *testing.T) {
+	for _, tc := range []struct {
+		count   int
+		percent float64
+		ok      bool
+	}{
+		{0, 0, true},
+		{10, 100, true},
+		{-1, 0, false},
+		{0, -1, false},
+		{0, 100.1, false},
+	} {
+		if err := validateDeleteLimits(tc.count, tc.percent); (err == nil) != tc.ok {
+			t.Errorf("%d, %g: got error %v", tc.count, tc.percent, err)
+		}
+	}
+}
@@ -0,0 +1,425 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"net"
+	"strings"
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/mcluseau/sync2kafka/client"
+	"github.com/mcluseau/sync2kafka/pb"
+)
+
+var (
+	grpcBind = flag.String("grpc-bind", "", "gRPC API bind address (disabled if empty); uses -tls-key and -tls-cert if set")
+
+	grpcServer *grpc.Server
+)
+
+// grpcAPI implements the gRPC service, the values being synchronized in the binary format.
+type grpcAPI struct {
+	pb.UnimplementedSync2KafkaServer
+}
+
+func setupGRPC() {
+	if len(*grpcBind) == 0 {
+		return
+	}
+
+	opts := []grpc.ServerOption{}
+
+	if len(*tlsKeyPath) != 0 {
+		creds, err := credentials.NewServerTLSFromFile(*tlsCertPath, *tlsKeyPath)
+		if err != nil {
+			log.Fatal("failed to load gRPC TLS key pair: ", err)
+		}
+
+		opts = append(opts, grpc.Creds(creds))
+	}
+
+	grpcServer = grpc.NewServer(opts...)
+	pb.RegisterSync2KafkaServer(grpcServer, &grpcAPI{})
+
+	listener, err := net.Listen("tcp", *grpcBind)
+	if err != nil {
+		log.Fatalf("failed to listen on %s: %v", *grpcBind, err)
+	}
+
+	go func() {
+		log.Print("gRPC listening on ", *grpcBind)
+		if err := grpcServer.Serve(listener); err != nil {
+			log.Fatal("gRPC server failed: ", err)
+		}
+	}()
+}
+
+func grpcBearerToken(ctx context.Context) string {
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	for _, hdr := range md.Get("authorization") {
+		if strings.HasPrefix(hdr, bearerHdr) {
+			return hdr[len(bearerHdr):]
+		}
+	}
+
+	return ""
+}
+
+// checkAdmin checks the HTTP API token, if any.
+func (a *grpcAPI) checkAdmin(ctx context.Context) error {
+	if len(*httpToken) != 0 && grpcBearerToken(ctx) != *httpToken {
+		return status.Error(codes.Unauthenticated, "unauthorized")
+	}
+	return nil
+}
+
+var grpcCodes = map[string]codes.Code{
+	client.ErrorCodeBadRequest:        codes.InvalidArgument,
+	client.ErrorCodeUnauthorized:      codes.Unauthenticated,
+	client.ErrorCodeForbidden:         codes.PermissionDenied,
+	client.ErrorCodeTopicNotAllowed:   codes.PermissionDenied,
+	client.ErrorCodeTopicLocked:       codes.Aborted,
+	client.ErrorCodeTopicNotFound:     codes.NotFound,
+	client.ErrorCodeTopicNotCompacted: codes.FailedPrecondition,
+	client.ErrorCodeTooManyDeletes:    codes.FailedPrecondition,
+	client.ErrorCodeUnknownFormat:     codes.InvalidArgument,
+	client.ErrorCodeReadFailed:        codes.InvalidArgument,
+	client.ErrorCodeSyncFailed:        codes.Unavailable,
+	client.ErrorCodeCancelled:         codes.Canceled,
+	client.ErrorCodeInternal:          codes.Internal,
+}
+
+// grpcError converts a failed sync result to a gRPC status error.
+func grpcError(result SyncResult) error {
+	code, ok := grpcCodes[result.ErrorCode]
+	if !ok {
+		code = codes.Unknown
+	}
+
+	st := status.New(code, result.Error)
+
+	info := &errdetails.ErrorInfo{
+		Reason: result.ErrorCode,
+		Domain: "sync2kafka",
+	}
+
+	if len(result.ConnectionID) != 0 {
+		info.Metadata = map[string]string{"connectionId": result.ConnectionID}
+	}
+
+	if detailed, err := st.WithDetails(info); err == nil {
+		st = detailed
+	}
+
+	return st.Err()
+}
+
+func (a *grpcAPI) Sync(stream pb.Sync2Kafka_SyncServer) error {
+	if isShuttingDown() {
+		return status.Error(codes.Unavailable, "shutting down")
+	}
+
+	activeConns.Add(1)
+	defer activeConns.Done()
+
+	ctx := stream.Context()
+
+	remote := ""
+	if p, ok := peer.FromContext(ctx); ok {
+		remote = p.Addr.String()
+	}
+
+	connStatus := newConnStatus(remote)
+	defer connStatus.Finished()
+
+	logPrefix := fmt.Sprintf("%s from %v (gRPC): ", connStatus.ID, connStatus.Remote)
+
+	log.Print(logPrefix, "new sync stream")
+
+	activeConnections.Inc()
+	defer activeConnections.Dec()
+
+	go func() {
+		// the client cancelled the call, or the call ended
+		<-ctx.Done()
+		connStatus.Cancel()
+	}()
+
+	req, err := stream.Recv()
+	if err != nil {
+		log.Print(logPrefix, "failed to read init message: ", err)
+		return err
+	}
+
+	initMsg := req.GetInit()
+	if initMsg == nil {
+		err := newSyncError(client.ErrorCodeBadRequest, "the first message must be the init")
+		log.Print(logPrefix, "rejecting: ", err)
+		return grpcError(errorResult(err))
+	}
+
+	init := &SyncInitInfo{
+		Format:             "binary",
+		Token:              grpcBearerToken(ctx),
+		Topic:              initMsg.Topic,
+		DoDelete:           initMsg.DoDelete,
+		Mode:               initMsg.Mode,
+		Force:              initMsg.Force,
+		Headers:            initMsg.Headers,
+		DryRun:             initMsg.DryRun,
+		DryRunSampleSize:   int(initMsg.DryRunSampleSize),
+		WaitForLock:        initMsg.WaitForLock,
+		WaitForLockTimeout: int(initMsg.WaitForLockTimeout.AsDuration() / time.Second),
+	}
+
+	session := &syncSession{
+		status:    connStatus,
+		init:      init,
+		logPrefix: logPrefix,
+		endOnEOF:  true,
+		readKVs: func(out chan KeyValue, status *ConnStatus) error {
+			reqs := recvSyncRequests(stream, status.cancel)
+
+			for {
+				var req grpcSyncRequest
+				select {
+				case req = <-reqs:
+				case <-status.cancel:
+					return errCancelled
+				}
+
+				if req.err != nil {
+					return req.err
+				}
+
+				kv := req.GetKv()
+				if kv == nil {
+					return errors.New("expected a key/value message")
+				}
+
+				status.ItemsRead++
+
+				value := kv.Value
+				if value == nil && !kv.Delete {
+					// proto3 doesn't distinguish empty from missing
+					value = []byte{}
+				}
+
+				item, err := toKV(status, kv.Key, value, kv.Delete)
+				if err != nil {
+					return err
+				}
+
+				select {
+				case out <- item:
+				case <-status.cancel:
+					return errCancelled
+				}
+			}
+		},
+	}
+
+	result := session.run()
+	if !result.OK {
+		return grpcError(result)
+	}
+
+	return stream.SendAndClose(pbSyncResult(result))
+}
+
+type grpcSyncRequest struct {
+	*pb.SyncRequest
+	err error
+}
+
+// recvSyncRequests receives the stream's requests in the background, as Recv can't be interrupted
+// (it returns when the call ends), until an error or stop is closed.
+func recvSyncRequests(stream pb.Sync2Kafka_SyncServer, stop chan bool) chan grpcSyncRequest {
+	reqs := make(chan grpcSyncRequest)
+
+	go func() {
+		for {
+			req, err := stream.Recv()
+
+			select {
+			case reqs <- grpcSyncRequest{req, err}:
+			case <-stop:
+				return
+			}
+
+			if err != nil {
+				return
+			}
+		}
+	}()
+
+	return reqs
+}
+
+func pbSyncResult(result SyncResult) *pb.SyncResult {
+	res := &pb.SyncResult{
+		ConnectionId: result.ConnectionID,
+	}
+
+	if s := result.Stats; s != nil {
+		res.Stats = &pb.SyncStats{
+			ItemsRead:         s.ItemsRead,
+			Created:           s.Created,
+			Modified:          s.Modified,
+			Deleted:           s.Deleted,
+			Unchanged:         s.Unchanged,
+			Count:             s.Count,
+			Sent:              s.Sent,
+			Successes:         s.Successes,
+			Errors:            s.Errors,
+			MessagesInTopic:   s.MessagesInTopic,
+			ReadTopicDuration: durationpb.New(s.ReadTopicDuration),
+			SyncDuration:      durationpb.New(s.SyncDuration),
+			TotalDuration:     durationpb.New(s.TotalDuration),
+		}
+	}
+
+	if result.DryRun != nil {
+		res.DryRunWarning = result.DryRun.Warning
+
+		for _, changed := range result.DryRun.Sample {
+			// binary keys are JSON encoded
+			key := []byte{}
+			json.Unmarshal(changed.Key, &key)
+
+			res.DryRunSample = append(res.DryRunSample, &pb.ChangedKey{Change: changed.Change, Key: key})
+		}
+	}
+
+	return res
+}
+
+func pbConnection(cs *ConnStatus) *pb.Connection {
+	c := &pb.Connection{
+		Id:            cs.ID,
+		Remote:        cs.Remote,
+		Identity:      cs.Identity,
+		Status:        cs.Status,
+		TargetTopic:   cs.TargetTopic,
+		DryRun:        cs.DryRun,
+		ItemsRead:     cs.ItemsRead,
+		StartTime:     timestamppb.New(cs.StartTime),
+		QueuePosition: int32(cs.QueuePosition),
+	}
+
+	if !cs.EndTime.IsZero() {
+		c.EndTime = timestamppb.New(cs.EndTime)
+	}
+
+	return c
+}
+
+func (a *grpcAPI) ListConnections(ctx context.Context, _ *pb.ListConnectionsRequest) (*pb.ConnectionList, error) {
+	if err := a.checkAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	connStatusesMutex.Lock()
+	defer connStatusesMutex.Unlock()
+
+	list := &pb.ConnectionList{}
+	for _, cs := range connStatuses {
+		list.Connections = append(list.Connections, pbConnection(cs))
+	}
+
+	return list, nil
+}
+
+func (a *grpcAPI) GetConnection(ctx context.Context, ref *pb.ConnectionRef) (*pb.Connection, error) {
+	if err := a.checkAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	cs := getConnStatus(ref.Id)
+	if cs == nil {
+		return nil, status.Error(codes.NotFound, "no such connection")
+	}
+
+	return pbConnection(cs), nil
+}
+
+func (a *grpcAPI) CancelConnection(ctx context.Context, ref *pb.ConnectionRef) (*pb.Connection, error) {
+	if err := a.checkAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	cs := getConnStatus(ref.Id)
+	if cs == nil {
+		return nil, status.Error(codes.NotFound, "no such connection")
+	}
+
+	if !cs.EndTime.IsZero() {
+		return nil, status.Error(codes.FailedPrecondition, "connection already finished")
+	}
+
+	log.Printf("gRPC API: cancelling connection %s", cs.ID)
+	cs.Cancel()
+
+	return pbConnection(cs), nil
+}
+
+func (a *grpcAPI) CancelTopicSync(ctx context.Context, ref *pb.TopicRef) (*pb.Connection, error) {
+	if err := a.checkAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	cs := getTopicConnStatus(ref.Topic)
+	if cs == nil {
+		return nil, status.Error(codes.NotFound, "no sync running on this topic")
+	}
+
+	log.Printf("gRPC API: cancelling sync of topic %q (connection %s)", ref.Topic, cs.ID)
+	cs.Cancel()
+
+	return pbConnection(cs), nil
+}
+
+func (a *grpcAPI) IndexTopic(ctx context.Context, ref *pb.TopicRef) (*pb.IndexTopicResult, error) {
+	if err := a.checkAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	if !hasStore {
+		return nil, status.Error(codes.FailedPrecondition, "no store")
+	}
+
+	if len(ref.Topic) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "no topic specified")
+	}
+
+	if !isTopicAllowed(ref.Topic) {
+		return nil, status.Errorf(codes.PermissionDenied, "topic %q not allowed", ref.Topic)
+	}
+
+	startTime := time.Now()
+
+	msgCount, err := indexTopic(ref.Topic)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "indexing failed: %v", err)
+	}
+
+	return &pb.IndexTopicResult{
+		MessagesRead: msgCount,
+		Duration:     durationpb.New(time.Since(startTime)),
+	}, nil
+}
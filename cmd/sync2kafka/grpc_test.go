@@ -0,0 +1,64 @@
+package main
+
+import (
+	"io"
+	"testing"
+	"time"
+
+	"github.com/mcluseau/sync2kafka/pb"
+)
+
+// blockingSyncStream returns its requests, then blocks until released and returns io.EOF.
+type blockingSyncStream struct {
+	pb.Sync2Kafka_SyncServer
+	reqs    []*pb.SyncRequest
+	release chan bool
+}
+
+func (s *blockingSyncStream) Recv() (*pb.SyncRequest, error) {
+	if len(s.reqs) != 0 {
+		req := s.reqs[0]
+		s.reqs = s.reqs[1:]
+		return req, nil
+	}
+
+	<-s.release
+	return nil, io.EOF
+}
+
+func TestRecvSyncRequests(t *testing.T) {
+	stream := &blockingSyncStream{
+		reqs:    []*pb.SyncRequest{{}},
+		release: make(chan bool),
+	}
+	stop := make(chan bool)
+
+	reqs := recvSyncRequests(stream, stop)
+
+	select {
+	case req := <-reqs:
+		if req.err != nil || req.SyncRequest == nil {
+			t.Fatalf("got %+v", req)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("no request received")
+	}
+
+	// nothing is received while Recv blocks
+	select {
+	case req := <-reqs:
+		t.Fatalf("unexpected request %+v", req)
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	close(stream.release)
+
+	select {
+	case req := <-reqs:
+		if req.err != io.EOF {
+			t.Errorf("got error %v, expected io.EOF", req.err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("no error received")
+	}
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Shopify/sarama"
+	diff "github.com/mcluseau/go-diff"
+)
+
+// headerPrefix prefixes the names of the headers set by the server.
+const headerPrefix = "sync2kafka-"
+
+var messageHeaders = flag.String("message-headers", "",
+	"Headers added to the produced messages (comma separated): sync-id, source, operation, client (the sync's headers); requires kafka-version >= 0.11")
+
+func messageHeaderEnabled(name string) bool {
+	for _, h := range strings.Split(*messageHeaders, ",") {
+		if strings.TrimSpace(h) == name {
+			return true
+		}
+	}
+	return false
+}
+
+func validateMessageHeadersConfig(conf *sarama.Config) (errs []error) {
+	if len(*messageHeaders) == 0 {
+		return
+	}
+
+	for _, h := range strings.Split(*messageHeaders, ",") {
+		switch strings.TrimSpace(h) {
+		case "sync-id", "source", "operation", "client":
+		default:
+			errs = append(errs, fmt.Errorf("unknown message header: %q", h))
+		}
+	}
+
+	if conf != nil && !conf.Version.IsAtLeast(sarama.V0_11_0_0) {
+		errs = append(errs, errors.New("message-headers require kafka-version >= 0.11"))
+	}
+
+	return
+}
+
+// checkClientHeaders verifies the headers given by a client.
+func checkClientHeaders(headers map[string]string) error {
+	for name := range headers {
+		if len(name) == 0 {
+			return errors.New("empty header name")
+		}
+		if strings.HasPrefix(name, headerPrefix) {
+			return fmt.Errorf("header %q: the %q prefix is reserved", name, headerPrefix)
+		}
+	}
+	return nil
+}
+
+// syncHeaders returns the enabled headers common to all the messages of a sync.
+func syncHeaders(syncID, source string, clientHeaders map[string]string) (headers []sarama.RecordHeader) {
+	add := func(name, value string) {
+		headers = append(headers, sarama.RecordHeader{Key: []byte(name), Value: []byte(value)})
+	}
+
+	if messageHeaderEnabled("sync-id") {
+		add(headerPrefix+"sync-id", syncID)
+	}
+
+	if messageHeaderEnabled("source") {
+		add(headerPrefix+"source", source)
+	}
+
+	if messageHeaderEnabled("client") {
+		names := make([]string, 0, len(clientHeaders))
+		for name := range clientHeaders {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			add(name, clientHeaders[name])
+		}
+	}
+
+	return
+}
+
+// operationHeader returns the operation header of a change.
+func operationHeader(changeType diff.ChangeType) sarama.RecordHeader {
+	var op string
+	switch changeType {
+	case diff.Created:
+		op = "create"
+	case diff.Modified:
+		op = "update"
+	case diff.Deleted:
+		op = "delete"
+	}
+
+	return sarama.RecordHeader{Key: []byte(headerPrefix + "operation"), Value: []byte(op)}
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/Shopify/sarama"
+	diff "github.com/mcluseau/go-diff"
+)
+
+func TestSyncHeaders(t *testing.T) {
+	defer func(prev string) { *messageHeaders = prev }(*messageHeaders)
+
+	clientHeaders := map[string]string{"b": "2", "a": "1"}
+
+	for _, tc := range []struct {
+		enabled  string
+		expected string
+	}{
+		{"", "[]"},
+		{"operation", "[]"},
+		{"sync-id", "[sync2kafka-sync-id=id]"},
+		{"source", "[sync2kafka-source=src]"},
+		{"client", "[a=1 b=2]"},
+		{" client , sync-id,source", "[sync2kafka-sync-id=id sync2kafka-source=src a=1 b=2]"},
+	} {
+		*messageHeaders = tc.enabled
+
+		if headers := formatHeaders(syncHeaders("id", "src", clientHeaders)); headers != tc.expected {
+			t.Errorf("%q: got %s, expected %s", tc.enabled, headers, tc.expected)
+		}
+	}
+}
+
+func TestOperationHeader(t *testing.T) {
+	for _, tc := range []struct {
+		changeType diff.ChangeType
+		expected   string
+	}{
+		{diff.Created, "[sync2kafka-operation=create]"},
+		{diff.Modified, "[sync2kafka-operation=update]"},
+		{diff.Deleted, "[sync2kafka-operation=delete]"},
+	} {
+		if header := formatHeaders([]sarama.RecordHeader{operationHeader(tc.changeType)}); header != tc.expected {
+			t.Errorf("%v: got %s, expected %s", tc.changeType, header, tc.expected)
+		}
+	}
+}
+
+func TestCheckClientHeaders(t *testing.T) {
+	for _, tc := range []struct {
+		headers map[string]string
+		ok      bool
+	}{
+		{nil, true},
+		{map[string]string{"x-app": "a", "sync2kafka": "b"}, true},
+		{map[string]string{"": "a"}, false},
+		{map[string]string{"sync2kafka-source": "a"}, false},
+	} {
+		if err := checkClientHeaders(tc.headers); (err == nil) != tc.ok {
+			t.Errorf("%v: got error %v", tc.headers, err)
+		}
+	}
+}
+
+func TestValidateMessageHeadersConfig(t *testing.T) {
+	defer func(prev string) { *messageHeaders = prev }(*messageHeaders)
+
+	v0_10 := sarama.NewConfig()
+	v0_10.Version = sarama.V0_10_2_0
+
+	v0_11 := sarama.NewConfig()
+	v0_11.Version = sarama.V0_11_0_0
+
+	for _, tc := range []struct {
+		enabled string
+		conf    *sarama.Config
+		errors  int
+	}{
+		{"", v0_10, 0},
+		{"sync-id,source,operation,client", v0_11, 0},
+		{"sync-id", v0_10, 1},
+		{"sync-id,unknown", v0_11, 1},
+	} {
+		*messageHeaders = tc.enabled
+
+		if errs := validateMessageHeadersConfig(tc.conf); len(errs) != tc.errors {
+			t.Errorf("%q: got errors %v, expected %d", tc.enabled, errs, tc.errors)
+		}
+	}
+}
+
+func formatHeaders(headers []sarama.RecordHeader) string {
+	s := make([]string, 0, len(headers))
+	for _, h := range headers {
+		s = append(s, string(h.Key)+"="+string(h.Value))
+	}
+	return fmt.Sprint(s)
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+
+	restful "github.com/emicklei/go-restful"
+)
+
+const defaultHistoryLimit = 100
+
+type historyAPI struct{}
+
+func (a *historyAPI) Register(ws *restful.WebService) {
+	limitParam := ws.QueryParameter("limit", "Maximum number of records to return").DataType("integer")
+
+	ws.Route(ws.GET("/syncs").To(a.List).Param(limitParam).
+		Writes([]SyncRecord{}))
+	ws.Route(ws.GET("/syncs/{sync-id}").To(a.Get).
+		Param(ws.PathParameter("sync-id", "ID of the sync")).
+		Writes(SyncRecord{}))
+	ws.Route(ws.GET("/topics/{topic}/syncs").To(a.ListTopic).Param(limitParam).
+		Param(ws.PathParameter("topic", "Name of the topic")).
+		Writes([]SyncRecord{}))
+}
+
+func (a *historyAPI) fail(req *restful.Request, res *restful.Response, err error) {
+	log.Printf("history API: %s: failed: %v", req.Request.URL.Path, err)
+	res.WriteErrorString(http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+}
+
+func (a *historyAPI) limit(req *restful.Request) int {
+	limit, err := strconv.Atoi(req.QueryParameter("limit"))
+	if err != nil || limit <= 0 {
+		return defaultHistoryLimit
+	}
+	return limit
+}
+
+func (a *historyAPI) List(req *restful.Request, res *restful.Response) {
+	records, err := listSyncs(a.limit(req), nil)
+	if err != nil {
+		a.fail(req, res, err)
+		return
+	}
+
+	res.WriteEntity(records)
+}
+
+func (a *historyAPI) ListTopic(req *restful.Request, res *restful.Response) {
+	topic := req.PathParameter("topic")
+
+	records, err := listSyncs(a.limit(req), func(rec *SyncRecord) bool {
+		return rec.Topic == topic
+	})
+	if err != nil {
+		a.fail(req, res, err)
+		return
+	}
+
+	res.WriteEntity(records)
+}
+
+func (a *historyAPI) Get(req *restful.Request, res *restful.Response) {
+	rec, err := getSync(req.PathParameter("sync-id"))
+	if err != nil {
+		a.fail(req, res, err)
+		return
+	}
+
+	if rec == nil {
+		http.NotFound(res.ResponseWriter, req.Request)
+		return
+	}
+
+	res.WriteEntity(rec)
+}
@@ -0,0 +1,176 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"log"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/boltdb/bolt"
+	"github.com/oklog/ulid"
+
+	"github.com/mcluseau/sync2kafka/client"
+)
+
+var (
+	historyRetention = flag.Duration("sync-history-retention", 7*24*time.Hour, "How long to keep the sync history (0 to disable history)")
+
+	historyBucket = []byte("history:syncs")
+
+	ulidEntropyMutex = sync.Mutex{}
+	ulidEntropy      = ulid.Monotonic(rand.New(rand.NewSource(time.Now().UnixNano())), 0)
+)
+
+// SyncRecord is the history entry of a sync.
+type SyncRecord struct {
+	ID        string
+	Topic     string
+	Remote    string
+	Identity  string
+	DryRun    bool
+	DoDelete  bool
+	Mode      string
+	StartTime time.Time
+	EndTime   time.Time
+	ItemsRead int64
+	Stats     *client.SyncStats
+	OK        bool
+	ErrorCode string
+	Error     string
+}
+
+func newULID() ulid.ULID {
+	ulidEntropyMutex.Lock()
+	defer ulidEntropyMutex.Unlock()
+
+	return ulid.MustNew(ulid.Now(), ulidEntropy)
+}
+
+func hasHistory() bool {
+	return hasStore && *historyRetention > 0
+}
+
+func recordSync(rec *SyncRecord) {
+	if !hasHistory() {
+		return
+	}
+
+	ba, err := json.Marshal(rec)
+	if err != nil {
+		log.Print("failed to encode sync record: ", err)
+		return
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		b, err := tx.CreateBucketIfNotExists(historyBucket)
+		if err != nil {
+			return err
+		}
+
+		return b.Put([]byte(rec.ID), ba)
+	})
+
+	if err != nil {
+		log.Printf("failed to record sync %s: %v", rec.ID, err)
+	}
+}
+
+// listSyncs returns the most recent records first, up to limit records matching the filter.
+func listSyncs(limit int, filter func(*SyncRecord) bool) (records []*SyncRecord, err error) {
+	records = make([]*SyncRecord, 0)
+
+	err = db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket(historyBucket)
+		if b == nil {
+			return nil
+		}
+
+		c := b.Cursor()
+		for k, v := c.Last(); k != nil && len(records) < limit; k, v = c.Prev() {
+			rec := &SyncRecord{}
+			if err := json.Unmarshal(v, rec); err != nil {
+				return err
+			}
+
+			if filter != nil && !filter(rec) {
+				continue
+			}
+
+			records = append(records, rec)
+		}
+
+		return nil
+	})
+	return
+}
+
+func getSync(id string) (rec *SyncRecord, err error) {
+	err = db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket(historyBucket)
+		if b == nil {
+			return nil
+		}
+
+		v := b.Get([]byte(id))
+		if v == nil {
+			return nil
+		}
+
+		rec = &SyncRecord{}
+		return json.Unmarshal(v, rec)
+	})
+	return
+}
+
+func syncHistoryCleaner() {
+	for range time.Tick(time.Hour) {
+		cleanupSyncHistory()
+	}
+}
+
+func cleanupSyncHistory() {
+	minID := ulid.ULID{}
+	if err := minID.SetTime(ulid.Timestamp(time.Now().Add(-*historyRetention))); err != nil {
+		log.Print("sync history cleanup failed: ", err)
+		return
+	}
+
+	minKey := []byte(minID.String())
+
+	var count int
+
+	err := db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(historyBucket)
+		if b == nil {
+			return nil
+		}
+
+		expired := make([][]byte, 0)
+
+		c := b.Cursor()
+		for k, _ := c.First(); k != nil && bytes.Compare(k, minKey) < 0; k, _ = c.Next() {
+			expired = append(expired, append([]byte{}, k...))
+		}
+
+		for _, k := range expired {
+			if err := b.Delete(k); err != nil {
+				return err
+			}
+		}
+
+		count = len(expired)
+		return nil
+	})
+
+	if err != nil {
+		log.Print("sync history cleanup failed: ", err)
+		return
+	}
+
+	if count != 0 {
+		log.Printf("sync history cleanup: removed %d records", count)
+	}
+}
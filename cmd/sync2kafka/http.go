@@ -1,8 +1,10 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"log"
+	"net"
 	"net/http"
 	"strings"
 
@@ -16,6 +18,8 @@ const bearerHdr = "Bearer "
 var (
 	httpBind  = flag.String("http-bind", ":8080", "HTTP API bind port")
 	httpToken = flag.String("http-token", "", "Bearer token for API access")
+
+	httpServer *http.Server
 )
 
 func setupHTTP() {
@@ -30,29 +34,63 @@ func setupHTTP() {
 
 		ws.Route(ws.GET("/connections").Writes(connStatuses).To(httpGetConnections))
 
+		connIDParam := ws.PathParameter("connection-id", "ID of the connection")
+		ws.Route(ws.GET("/connections/{connection-id}").Param(connIDParam).Writes(ConnStatus{}).To(httpGetConnection))
+		ws.Route(ws.DELETE("/connections/{connection-id}").Param(connIDParam).To(httpCancelConnection))
+
+		ws.Route(ws.POST("/topics/{topic}/cancel").Param(ws.PathParameter("topic", "Name of the topic")).
+			Writes(ConnStatus{}).To(httpCancelTopicSync))
+
+		ws.Route(ws.GET("/config/reload").Writes(ReloadStatus{}).To(httpGetReloadStatus))
+		ws.Route(ws.POST("/config/reload").Writes(ReloadStatus{}).To(httpReloadConfig))
+
 		if hasStore {
 			(&storeAPI{}).Register(ws)
 		}
 
+		if hasHistory() {
+			(&historyAPI{}).Register(ws)
+		}
+
 		restful.Add(ws)
+
+		(&syncAPI{}).Register()
 	})
 
 	swaggerui.HandleAt("/swagger-ui/")
+	setupMetrics()
+	setupReadiness()
+
+	httpServer = &http.Server{
+		Addr:    *httpBind,
+		Handler: restful.DefaultContainer,
+		ConnContext: func(ctx context.Context, c net.Conn) context.Context {
+			return context.WithValue(ctx, httpConnKey{}, c)
+		},
+	}
 
 	go func() {
 		var err error
 		if len(*tlsKeyPath) == 0 {
 			log.Print("HTTP listening on ", *httpBind)
-			err = http.ListenAndServe(*httpBind, restful.DefaultContainer)
+			err = httpServer.ListenAndServe()
 		} else {
 			log.Print("HTTPS listening on ", *httpBind)
-			err = http.ListenAndServeTLS(*httpBind, *tlsCertPath, *tlsKeyPath, restful.DefaultContainer)
+			err = httpServer.ListenAndServeTLS(*tlsCertPath, *tlsKeyPath)
+		}
+
+		if err == http.ErrServerClosed {
+			// gracefulShutdown will exit
+			return
 		}
 
 		log.Fatal("http listen failed: ", err)
 	}()
 }
 
+// httpConnKey is the request context key of the request's connection.
+type httpConnKey struct{}
+
 func authFilter(req *restful.Request, res *restful.Response, chain *restful.FilterChain) {
 	if len(*httpToken) != 0 {
 		hdr := req.HeaderParameter("Authorization")
@@ -76,3 +114,58 @@ func httpGetConnections(req *restful.Request, res *restful.Response) {
 	defer connStatusesMutex.Unlock()
 	res.WriteEntity(connStatuses)
 }
+
+func httpGetConnection(req *restful.Request, res *restful.Response) {
+	cs := getConnStatus(req.PathParameter("connection-id"))
+	if cs == nil {
+		http.NotFound(res.ResponseWriter, req.Request)
+		return
+	}
+
+	res.WriteEntity(cs)
+}
+
+func httpCancelConnection(req *restful.Request, res *restful.Response) {
+	cs := getConnStatus(req.PathParameter("connection-id"))
+	if cs == nil {
+		http.NotFound(res.ResponseWriter, req.Request)
+		return
+	}
+
+	if !cs.EndTime.IsZero() {
+		res.WriteErrorString(http.StatusConflict, "connection already finished")
+		return
+	}
+
+	log.Printf("HTTP API: cancelling connection %s", cs.ID)
+	cs.Cancel()
+}
+
+func httpCancelTopicSync(req *restful.Request, res *restful.Response) {
+	topic := req.PathParameter("topic")
+
+	cs := getTopicConnStatus(topic)
+	if cs == nil {
+		res.WriteErrorString(http.StatusNotFound, "no sync running on this topic")
+		return
+	}
+
+	log.Printf("HTTP API: cancelling sync of topic %q (connection %s)", topic, cs.ID)
+	cs.Cancel()
+
+	res.WriteEntity(cs)
+}
+
+func httpGetReloadStatus(req *restful.Request, res *restful.Response) {
+	res.WriteEntity(getReloadStatus())
+}
+
+func httpReloadConfig(req *restful.Request, res *restful.Response) {
+	status := reloadAccess("HTTP API")
+	if !status.OK {
+		res.WriteHeaderAndEntity(http.StatusUnprocessableEntity, status)
+		return
+	}
+
+	res.WriteEntity(status)
+}
@@ -1,9 +1,12 @@
 package main
 
 import (
+	"errors"
 	"flag"
+	"fmt"
 	"log"
 	"sync"
+	"time"
 
 	"github.com/mcluseau/go-diff/boltindex"
 	kafkasync "github.com/mcluseau/kafka-sync"
@@ -13,26 +16,38 @@ var (
 	indexingTopicsCond = sync.NewCond(&sync.Mutex{})
 	indexingTopics     = map[string]bool{}
 
+	// running indexings, waited for before closing the store
+	indexers = sync.WaitGroup{}
+
+	errIndexingStopped = errors.New("indexing stopped: shutting down")
+
 	maxIndexings = flag.Int("parallel-indexers", 4, "Maximum parallel indexing operations")
 )
 
-func indexTopic(topic string) (err error) {
+func indexTopic(topic string) (msgCount uint64, err error) {
 	if !hasStore {
 		return
 	}
 
-	lockTopicForIndexing(topic)
+	if !lockTopicForIndexing(topic) {
+		return 0, errIndexingStopped
+	}
 	defer unlockTopicForIndexing(topic)
 
-	index, err := boltindex.New(db, []byte(topic), false)
+	syncer := kafkasync.New(topic)
+	syncer.Partition = getTopicConfig(topic).Partition
+
+	index, err := boltindex.New(db, indexBucket(topic, syncer.Partition), false)
 	if err != nil {
 		return
 	}
 
-	syncer := kafkasync.New(topic)
-
 	log.Printf("indexing topic %s...", topic)
-	msgCount, err := syncer.IndexTopic(kafka, index)
+	startTime := time.Now()
+	msgCount, err = syncer.IndexTopic(kafka, index)
+
+	indexingDuration.WithLabelValues(topic).Observe(time.Since(startTime).Seconds())
+	messagesIndexed.WithLabelValues(topic).Add(float64(msgCount))
 
 	log.Printf("indexing topic %s: %d messages read", topic, msgCount)
 
@@ -47,14 +62,35 @@ func indexTopic(topic string) (err error) {
 	return
 }
 
-func lockTopicForIndexing(topic string) {
+// indexBucket returns the store bucket of a topic's partition index, so a partition change
+// doesn't resume from (and diff against) another partition's messages. Partition 0 keeps the
+// topic's name, as before partitions were configurable.
+func indexBucket(topic string, partition int32) []byte {
+	if partition == 0 {
+		return []byte(topic)
+	}
+	return []byte(fmt.Sprintf("%s:%d", topic, partition))
+}
+
+// lockTopicForIndexing waits for an indexing slot, returning false when shutting down.
+func lockTopicForIndexing(topic string) bool {
 	indexingTopicsCond.L.Lock()
+	defer indexingTopicsCond.L.Unlock()
+
 	for len(indexingTopics) >= *maxIndexings || indexingTopics[topic] {
+		if isShuttingDown() {
+			return false
+		}
 		indexingTopicsCond.Wait()
 	}
 
+	if isShuttingDown() {
+		return false
+	}
+
 	indexingTopics[topic] = true
-	indexingTopicsCond.L.Unlock()
+	indexers.Add(1)
+	return true
 }
 
 func unlockTopicForIndexing(topic string) {
@@ -62,5 +98,16 @@ func unlockTopicForIndexing(topic string) {
 	defer indexingTopicsCond.L.Unlock()
 
 	delete(indexingTopics, topic)
+	indexers.Done()
 	indexingTopicsCond.Broadcast()
 }
+
+// stopIndexers prevents new indexings and waits for the running ones, returning false on timeout.
+func stopIndexers(timeout time.Duration) bool {
+	// isShuttingDown() is already true, so no indexer is added after this
+	indexingTopicsCond.L.Lock()
+	indexingTopicsCond.Broadcast()
+	indexingTopicsCond.L.Unlock()
+
+	return waitGroup(&indexers, timeout)
+}
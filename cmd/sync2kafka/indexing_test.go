@@ -0,0 +1,21 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestIndexBucket(t *testing.T) {
+	for _, tc := range []struct {
+		topic     string
+		partition int32
+		expected  string
+	}{
+		{"ref.customers", 0, "ref.customers"},
+		{"ref.customers", 1, "ref.customers:1"},
+		{"ref.customers", 12, "ref.customers:12"},
+	} {
+		if bucket := string(indexBucket(tc.topic, tc.partition)); bucket != tc.expected {
+			t.Errorf("%s/%d: got %q, expected %q", tc.topic, tc.partition, bucket, tc.expected)
+		}
+	}
+}
@@ -1,8 +1,13 @@
 package main
 
 import (
+	"crypto/tls"
+	"crypto/x509"
 	"flag"
+	"fmt"
+	"io/ioutil"
 	"log"
+	"os"
 	"strings"
 
 	"github.com/Shopify/sarama"
@@ -12,15 +17,39 @@ var (
 	kafkaBrokers = flag.String("brokers", "kafka:9092", "Kafka brokers, comma separated")
 	targetTopic  = flag.String("topic", "", "Kafka topic to synchronize")
 
+	kafkaClientID       = flag.String("kafka-client-id", "sync2kafka", "Kafka client ID")
+	kafkaVersion        = flag.String("kafka-version", "", "Kafka version of the brokers (ie: 2.3.0)")
+	kafkaCompression    = flag.String("kafka-compression", "none", "Kafka compression codec (none, gzip, snappy, lz4 or zstd)")
+	kafkaMaxMessageSize = flag.Int("kafka-max-message-bytes", 0, "Kafka maximum message size (0 for the default)")
+	kafkaIdempotent     = flag.Bool("kafka-idempotent", false, "Use the Kafka idempotent producer (requires version >= 0.11)")
+
+	kafkaTLS           = flag.Bool("kafka-tls", false, "Use TLS to connect to Kafka brokers")
+	kafkaTLSCA         = flag.String("kafka-tls-ca", "", "Kafka TLS CA certificates path (system ones if not set)")
+	kafkaTLSCert       = flag.String("kafka-tls-cert", "", "Kafka TLS client certificate path")
+	kafkaTLSKey        = flag.String("kafka-tls-key", "", "Kafka TLS client key path")
+	kafkaTLSSkipVerify = flag.Bool("kafka-tls-skip-verify", false, "Don't verify Kafka brokers' certificates")
+
+	kafkaSASLMechanism = flag.String("kafka-sasl-mechanism", "", "Kafka SASL mechanism (PLAIN, SCRAM-SHA-256 or SCRAM-SHA-512); no SASL if empty")
+	kafkaSASLUser      = flag.String("kafka-sasl-user", "", "Kafka SASL user")
+	kafkaSASLPassword  = flag.String("kafka-sasl-password", "", "Kafka SASL password (prefer -kafka-sasl-password-file or the KAFKA_SASL_PASSWORD env)")
+	kafkaSASLPassFile  = flag.String("kafka-sasl-password-file", "", "Kafka SASL password file path")
+
 	kafka sarama.Client
 )
 
-func setupKafka() {
-	conf := sarama.NewConfig()
-	conf.Producer.Return.Successes = true
-	conf.Producer.RequiredAcks = sarama.WaitForAll
+var compressionCodecs = map[string]sarama.CompressionCodec{
+	"none":   sarama.CompressionNone,
+	"gzip":   sarama.CompressionGZIP,
+	"snappy": sarama.CompressionSnappy,
+	"lz4":    sarama.CompressionLZ4,
+	"zstd":   sarama.CompressionZSTD,
+}
 
-	var err error
+func setupKafka() {
+	conf, err := kafkaConfig()
+	if err != nil {
+		log.Fatal("invalid Kafka configuration: ", err)
+	}
 
 	kafka, err = sarama.NewClient(strings.Split(*kafkaBrokers, ","), conf)
 	if err != nil {
@@ -29,3 +58,110 @@ func setupKafka() {
 
 	log.Print("connected to Kafka")
 }
+
+func kafkaConfig() (conf *sarama.Config, err error) {
+	conf = sarama.NewConfig()
+	conf.ClientID = *kafkaClientID
+	conf.Producer.Return.Successes = true
+	conf.Producer.RequiredAcks = sarama.WaitForAll
+
+	if len(*kafkaVersion) != 0 {
+		if conf.Version, err = sarama.ParseKafkaVersion(*kafkaVersion); err != nil {
+			return
+		}
+	}
+
+	codec, ok := compressionCodecs[*kafkaCompression]
+	if !ok {
+		return nil, fmt.Errorf("unknown compression codec: %q", *kafkaCompression)
+	}
+	conf.Producer.Compression = codec
+
+	if *kafkaMaxMessageSize > 0 {
+		conf.Producer.MaxMessageBytes = *kafkaMaxMessageSize
+	}
+
+	if *kafkaIdempotent {
+		conf.Producer.Idempotent = true
+		conf.Net.MaxOpenRequests = 1
+	}
+
+	if *kafkaTLS {
+		conf.Net.TLS.Enable = true
+		if conf.Net.TLS.Config, err = kafkaTLSConfig(); err != nil {
+			return
+		}
+	}
+
+	if len(*kafkaSASLMechanism) != 0 {
+		conf.Net.SASL.Enable = true
+		conf.Net.SASL.Handshake = true
+		conf.Net.SASL.User = *kafkaSASLUser
+		if conf.Net.SASL.Password, err = kafkaSASLPasswordValue(); err != nil {
+			return
+		}
+		conf.Net.SASL.Mechanism = sarama.SASLMechanism(*kafkaSASLMechanism)
+
+		switch conf.Net.SASL.Mechanism {
+		case sarama.SASLTypePlaintext:
+
+		case sarama.SASLTypeSCRAMSHA256:
+			conf.Net.SASL.SCRAMClientGeneratorFunc = func() sarama.SCRAMClient { return &scramClient{HashGeneratorFcn: scramSHA256} }
+
+		case sarama.SASLTypeSCRAMSHA512:
+			conf.Net.SASL.SCRAMClientGeneratorFunc = func() sarama.SCRAMClient { return &scramClient{HashGeneratorFcn: scramSHA512} }
+
+		default:
+			return nil, fmt.Errorf("unsupported SASL mechanism: %q", *kafkaSASLMechanism)
+		}
+	}
+
+	err = conf.Validate()
+	return
+}
+
+// kafkaSASLPasswordValue returns the password from the file, else the flag, else the environment.
+func kafkaSASLPasswordValue() (string, error) {
+	if len(*kafkaSASLPassFile) != 0 {
+		ba, err := ioutil.ReadFile(*kafkaSASLPassFile)
+		if err != nil {
+			return "", err
+		}
+		return strings.TrimRight(string(ba), "\r\n"), nil
+	}
+
+	if len(*kafkaSASLPassword) != 0 {
+		return *kafkaSASLPassword, nil
+	}
+
+	return os.Getenv("KAFKA_SASL_PASSWORD"), nil
+}
+
+func kafkaTLSConfig() (tlsConfig *tls.Config, err error) {
+	tlsConfig = &tls.Config{
+		InsecureSkipVerify: *kafkaTLSSkipVerify,
+	}
+
+	if len(*kafkaTLSCA) != 0 {
+		caCerts, err := ioutil.ReadFile(*kafkaTLSCA)
+		if err != nil {
+			return nil, err
+		}
+
+		tlsConfig.RootCAs = x509.NewCertPool()
+		if !tlsConfig.RootCAs.AppendCertsFromPEM(caCerts) {
+			return nil, fmt.Errorf("no certificate found in %s", *kafkaTLSCA)
+		}
+	}
+
+	if len(*kafkaTLSCert) != 0 {
+		cert, err := tls.LoadX509KeyPair(*kafkaTLSCert, *kafkaTLSKey)
+		if err != nil {
+			return nil, err
+		}
+
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	return
+}
@@ -23,16 +23,26 @@ func main() {
 	flag.Set("logtostderr", "true")
 	flag.Parse()
 
+	setupConfig()
+
 	go handleSignals()
 
 	setupStore()
 	setupKafka()
+	setupTopicAdmin()
+	setupLocks()
 	setupHTTP()
+	setupGRPC()
 
 	go connStatusCleaner()
+	go watchConfigFiles()
+
+	if hasHistory() {
+		go syncHistoryCleaner()
+	}
 
 	if len(*targetTopic) != 0 {
-		go indexTopic(*targetTopic)
+		go initialIndex(*targetTopic)
 	}
 
 	var tlsConfig *tls.Config
@@ -53,11 +63,17 @@ func main() {
 		log.Fatalf("failed to listen on %s: %v", *bindSpec, err)
 	}
 
+	mainListener = listener
+
 	log.Printf("listening on %s (TLS: %v)", *bindSpec, tlsMode)
 
 	for {
 		conn, err := listener.Accept()
 		if err != nil {
+			if isShuttingDown() {
+				// gracefulShutdown will exit
+				select {}
+			}
 			log.Fatal("listener failed: ", err)
 		}
 
@@ -74,17 +90,27 @@ func main() {
 			conn = tls.Server(conn, tlsConfig)
 		}
 
-		go handleConn(conn)
+		activeConns.Add(1)
+		go func() {
+			defer activeConns.Done()
+			handleConn(conn)
+		}()
 	}
 }
 
 func handleSignals() {
 	c := make(chan os.Signal, 1)
 
-	signal.Notify(c, syscall.SIGUSR1)
+	signal.Notify(c, syscall.SIGUSR1, syscall.SIGHUP, syscall.SIGTERM, syscall.SIGINT)
 
 	for sig := range c {
 		switch sig {
+		case syscall.SIGTERM, syscall.SIGINT:
+			go gracefulShutdown("got " + sig.String())
+
+		case syscall.SIGHUP:
+			reloadAccess("SIGHUP")
+
 		case syscall.SIGUSR1:
 			buf := make([]byte, 64*1024)
 			buf = buf[:runtime.Stack(buf, true)]
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/boltdb/bolt"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const metricsNamespace = "sync2kafka"
+
+var (
+	topicLabels = []string{"topic"}
+
+	syncsStarted = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Name:      "syncs_started_total",
+		Help:      "Number of syncs started",
+	}, topicLabels)
+
+	syncsSucceeded = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Name:      "syncs_succeeded_total",
+		Help:      "Number of syncs succeeded",
+	}, topicLabels)
+
+	syncsFailed = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Name:      "syncs_failed_total",
+		Help:      "Number of syncs failed",
+	}, topicLabels)
+
+	itemsRead = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Name:      "items_read_total",
+		Help:      "Number of key/values read from clients",
+	}, topicLabels)
+
+	messagesProduced = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Name:      "messages_produced_total",
+		Help:      "Number of messages produced to Kafka, by kind (create, update or delete)",
+	}, []string{"topic", "kind"})
+
+	syncDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: metricsNamespace,
+		Name:      "sync_duration_seconds",
+		Help:      "Duration of syncs",
+		Buckets:   prometheus.ExponentialBuckets(0.1, 2, 14),
+	}, topicLabels)
+
+	indexingDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: metricsNamespace,
+		Name:      "indexing_duration_seconds",
+		Help:      "Duration of topic indexing operations",
+		Buckets:   prometheus.ExponentialBuckets(0.1, 2, 14),
+	}, topicLabels)
+
+	messagesIndexed = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Name:      "messages_indexed_total",
+		Help:      "Number of messages read from Kafka by topic indexing",
+	}, topicLabels)
+
+	activeConnections = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Name:      "active_connections",
+		Help:      "Number of open sync connections",
+	})
+
+	lockedTopicsGauge = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Name:      "locked_topics",
+		Help:      "Number of topics locked by a sync",
+	})
+
+	_ = promauto.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Name:      "store_size_bytes",
+		Help:      "Size of the bolt store",
+	}, storeSize)
+)
+
+func setupMetrics() {
+	http.Handle("/metrics", promhttp.Handler())
+}
+
+func storeSize() (size float64) {
+	if !hasStore {
+		return 0
+	}
+
+	db.View(func(tx *bolt.Tx) error {
+		size = float64(tx.Size())
+		return nil
+	})
+	return
+}
+
+func recordSyncEnd(topic string, read int64, stats *SyncStats, dryRun bool, err error) {
+	itemsRead.WithLabelValues(topic).Add(float64(read))
+
+	if err != nil {
+		syncsFailed.WithLabelValues(topic).Inc()
+	} else {
+		syncsSucceeded.WithLabelValues(topic).Inc()
+	}
+
+	if stats == nil {
+		return
+	}
+
+	syncDuration.WithLabelValues(topic).Observe(stats.TotalDuration.Seconds())
+
+	if dryRun {
+		return
+	}
+
+	messagesProduced.WithLabelValues(topic, "create").Add(float64(stats.Created))
+	messagesProduced.WithLabelValues(topic, "update").Add(float64(stats.Modified))
+	messagesProduced.WithLabelValues(topic, "delete").Add(float64(stats.Deleted))
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"log"
+	"sync"
+
+	"github.com/Shopify/sarama"
+	diff "github.com/mcluseau/go-diff"
+	kafkasync "github.com/mcluseau/kafka-sync"
+)
+
+// setupProducer is kafkasync's SetupProducer, sending the changes with the sync's headers.
+func (spec *syncSpec) setupProducer(syncer kafkasync.Syncer, stats *SyncStats) (send func(diff.Change), finish func(), err error) {
+	producer, err := sarama.NewAsyncProducerFromClient(kafka)
+	if err != nil {
+		return
+	}
+
+	wg := &sync.WaitGroup{}
+
+	if kafka.Config().Producer.Return.Errors {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for prodError := range producer.Errors() {
+				log.Print(spec.LogPrefix, "producer error: ", prodError)
+				stats.ErrorCount++
+			}
+		}()
+	} else {
+		stats.ErrorCount = -1
+	}
+
+	if kafka.Config().Producer.Return.Successes {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range producer.Successes() {
+				stats.SuccessCount++
+			}
+		}()
+	} else {
+		stats.SuccessCount = -1
+	}
+
+	withOperation := messageHeaderEnabled("operation")
+	input := producer.Input()
+
+	send = func(change diff.Change) {
+		value := change.Value
+		if change.Type == diff.Deleted {
+			value = syncer.RemovedValue
+		}
+
+		headers := spec.Headers
+		if withOperation {
+			// don't share the array between messages
+			headers = append(headers[:len(headers):len(headers)], operationHeader(change.Type))
+		}
+
+		input <- &sarama.ProducerMessage{
+			Topic:     syncer.Topic,
+			Partition: syncer.Partition,
+			Key:       sarama.ByteEncoder(change.Key),
+			Value:     sarama.ByteEncoder(value),
+			Headers:   headers,
+		}
+		stats.SendCount++
+	}
+
+	finish = func() {
+		producer.AsyncClose()
+		wg.Wait()
+	}
+
+	return
+}
+
+// applyChanges is kafkasync's ApplyChanges, with the type of the sent changes.
+func applyChanges(changes <-chan diff.Change, send func(diff.Change), stats *SyncStats, cancel <-chan bool) {
+	for {
+		var (
+			change diff.Change
+			ok     bool
+		)
+
+		select {
+		case <-cancel:
+			return
+
+		case change, ok = <-changes:
+			if !ok {
+				return
+			}
+		}
+
+		switch change.Type {
+		case diff.Unchanged:
+			stats.Unchanged++
+			stats.Count++
+			continue
+
+		case diff.Created:
+			stats.Created++
+			stats.Count++
+
+		case diff.Modified:
+			stats.Modified++
+			stats.Count++
+
+		case diff.Deleted:
+			stats.Deleted++
+		}
+
+		send(change)
+	}
+}
@@ -0,0 +1,187 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/Shopify/sarama"
+	"github.com/boltdb/bolt"
+)
+
+var (
+	readyCheckTimeout = 5 * time.Second
+
+	// initial indexing retry delays
+	initialIndexMinDelay = time.Second
+	initialIndexMaxDelay = time.Minute
+
+	initialIndexMutex = sync.Mutex{}
+	initialIndexDone  bool
+	initialIndexErr   error
+)
+
+// Readiness is the /readyz response.
+type Readiness struct {
+	Ready  bool                  `json:"ready"`
+	Checks map[string]ReadyCheck `json:"checks"`
+}
+
+// ReadyCheck is the result of a readiness check.
+type ReadyCheck struct {
+	OK    bool   `json:"ok"`
+	Error string `json:"error,omitempty"`
+}
+
+func setupReadiness() {
+	http.HandleFunc("/readyz", httpReadyz)
+}
+
+// initialIndex indexes the default topic until it succeeds, recording the result for the readiness check.
+func initialIndex(topic string) {
+	delay := initialIndexMinDelay
+
+	for {
+		_, err := indexTopic(topic)
+
+		initialIndexMutex.Lock()
+		initialIndexDone = err == nil
+		initialIndexErr = err
+		initialIndexMutex.Unlock()
+
+		if err == nil || err == errIndexingStopped {
+			return
+		}
+
+		log.Printf("initial indexing of topic %s failed, retrying in %v", topic, delay)
+		time.Sleep(delay)
+
+		delay *= 2
+		if delay > initialIndexMaxDelay {
+			delay = initialIndexMaxDelay
+		}
+	}
+}
+
+func checkReadiness() (r Readiness) {
+	r.Checks = map[string]ReadyCheck{}
+
+	check := func(name string, err error) {
+		if err == nil {
+			r.Checks[name] = ReadyCheck{OK: true}
+		} else {
+			r.Checks[name] = ReadyCheck{Error: err.Error()}
+		}
+	}
+
+	if isShuttingDown() {
+		check("shutdown", errors.New("shutting down"))
+	}
+
+	check("kafka", withTimeout(checkKafka))
+
+	if hasStore {
+		check("store", withTimeout(checkStore))
+
+		if len(*targetTopic) != 0 {
+			check("index", checkInitialIndex())
+		}
+	}
+
+	r.Ready = true
+	for _, c := range r.Checks {
+		if !c.OK {
+			r.Ready = false
+		}
+	}
+
+	return
+}
+
+func withTimeout(check func() error) error {
+	result := make(chan error, 1)
+	go func() { result <- check() }()
+
+	select {
+	case err := <-result:
+		return err
+	case <-time.After(readyCheckTimeout):
+		return errors.New("timed out")
+	}
+}
+
+func checkKafka() error {
+	if kafka == nil || kafka.Closed() {
+		return errors.New("not connected")
+	}
+
+	if !kafka.Config().Version.IsAtLeast(sarama.V0_10_0_0) {
+		// no controller in older metadata responses
+		return checkKafkaBrokers()
+	}
+
+	broker, err := kafka.Controller()
+	if err != nil {
+		return err
+	}
+
+	if ok, err := broker.Connected(); !ok {
+		if err == nil {
+			err = errors.New("controller not connected")
+		}
+		return err
+	}
+
+	return nil
+}
+
+// checkKafkaBrokers succeeds if a broker is connected, else if the metadata can be refreshed
+// (connecting a broker).
+func checkKafkaBrokers() error {
+	for _, broker := range kafka.Brokers() {
+		if ok, _ := broker.Connected(); ok {
+			return nil
+		}
+	}
+
+	return kafka.RefreshMetadata()
+}
+
+func checkStore() error {
+	if db.IsReadOnly() {
+		return errors.New("read-only")
+	}
+
+	// fails if the store is closed
+	return db.View(func(tx *bolt.Tx) error { return nil })
+}
+
+func checkInitialIndex() error {
+	initialIndexMutex.Lock()
+	defer initialIndexMutex.Unlock()
+
+	if initialIndexDone {
+		return nil
+	}
+
+	if initialIndexErr != nil {
+		return fmt.Errorf("indexing failed, retrying: %v", initialIndexErr)
+	}
+
+	return errors.New("indexing in progress")
+}
+
+func httpReadyz(w http.ResponseWriter, _ *http.Request) {
+	r := checkReadiness()
+
+	w.Header().Set("Content-Type", "application/json")
+	if !r.Ready {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+
+	json.NewEncoder(w).Encode(r)
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/Shopify/sarama"
+)
+
+func TestCheckKafka(t *testing.T) {
+	defer func(prev sarama.Client) { kafka = prev }(kafka)
+
+	for _, version := range []sarama.KafkaVersion{sarama.V0_8_2_0, sarama.V0_10_0_0, sarama.V2_3_0_0} {
+		broker := sarama.NewMockBroker(t, 1)
+
+		broker.SetHandlerByMap(map[string]sarama.MockResponse{
+			"MetadataRequest": sarama.NewMockMetadataResponse(t).
+				SetBroker(broker.Addr(), broker.BrokerID()).
+				SetController(broker.BrokerID()),
+		})
+
+		conf := sarama.NewConfig()
+		conf.Version = version
+
+		var err error
+		if kafka, err = sarama.NewClient([]string{broker.Addr()}, conf); err != nil {
+			t.Fatal(err)
+		}
+
+		if err = checkKafka(); err != nil {
+			t.Errorf("version %v: %v", version, err)
+		}
+
+		kafka.Close()
+		broker.Close()
+
+		if err = checkKafka(); err == nil {
+			t.Errorf("version %v: no error with a closed client", version)
+		}
+	}
+}
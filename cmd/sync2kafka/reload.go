@@ -0,0 +1,163 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+var (
+	configWatchInterval = flag.Duration("config-watch-interval", 10*time.Second, "Interval between checks of configuration files changes (0 to disable)")
+
+	access atomic.Value // *accessRules
+
+	reloadMutex      = sync.Mutex{}
+	lastReload       ReloadStatus
+	watchedFileStats = map[string]fileStat{}
+)
+
+// accessRules are the reloadable authorization settings.
+type accessRules struct {
+	// auth is nil when using the single -token
+	auth *AuthConfig
+	// topics are the per-topic settings
+	topics map[string]*TopicConfig
+	// allowedTopics are the allowed topics file rules; nil when no file is given
+	allowedTopics *topicRules
+}
+
+// ReloadStatus is the outcome of the last configuration (re)load.
+type ReloadStatus struct {
+	Time  time.Time
+	OK    bool
+	Error string `json:",omitempty"`
+}
+
+type fileStat struct {
+	modTime time.Time
+	size    int64
+}
+
+func currentAccess() *accessRules {
+	return access.Load().(*accessRules)
+}
+
+// loadAccessRules reads the reloadable parts of the configuration files.
+func loadAccessRules() (rules *accessRules, err error) {
+	rules = &accessRules{
+		topics: map[string]*TopicConfig{},
+	}
+
+	if len(*configFile) != 0 {
+		cfg, errs := parseConfig(*configFile)
+		if len(errs) != 0 {
+			return nil, fmt.Errorf("%s:\n  - %s", *configFile, strings.Join(errs, "\n  - "))
+		}
+
+		if len(cfg.Tokens) != 0 {
+			rules.auth = &cfg.AuthConfig
+		}
+
+		if cfg.Topics != nil {
+			rules.topics = cfg.Topics
+		}
+	}
+
+	if len(*authFile) != 0 {
+		if rules.auth != nil {
+			return nil, fmt.Errorf("auth-file can't be used with tokens in the configuration file")
+		}
+
+		if rules.auth, err = loadAuthConfig(*authFile); err != nil {
+			return nil, fmt.Errorf("%s: %v", *authFile, err)
+		}
+	}
+
+	for topic, topicConfig := range rules.topics {
+		if err = topicConfig.validateTokens(rules.auth); err != nil {
+			return nil, fmt.Errorf("topics: %s: %v", topic, err)
+		}
+	}
+
+	if len(*allowedTopicsFile) != 0 {
+		if rules.allowedTopics, err = loadAllowedTopics(*allowedTopicsFile); err != nil {
+			return nil, fmt.Errorf("%s: %v", *allowedTopicsFile, err)
+		}
+	}
+
+	return
+}
+
+// reloadAccess reloads the access rules, keeping the current ones on failure.
+func reloadAccess(reason string) ReloadStatus {
+	reloadMutex.Lock()
+	defer reloadMutex.Unlock()
+
+	status := ReloadStatus{Time: time.Now()}
+
+	rules, err := loadAccessRules()
+	if err != nil {
+		status.Error = err.Error()
+		log.Printf("configuration reload (%s) failed, keeping the current one: %v", reason, err)
+
+	} else {
+		access.Store(rules)
+		status.OK = true
+		log.Printf("configuration reloaded (%s)", reason)
+	}
+
+	lastReload = status
+	return status
+}
+
+func getReloadStatus() ReloadStatus {
+	reloadMutex.Lock()
+	defer reloadMutex.Unlock()
+
+	return lastReload
+}
+
+func watchedFiles() (files []string) {
+	for _, f := range []string{*configFile, *authFile, *allowedTopicsFile} {
+		if len(f) != 0 {
+			files = append(files, f)
+		}
+	}
+	return
+}
+
+// filesChanged updates the files' stats, returning true if any changed.
+func filesChanged() (changed bool) {
+	for _, f := range watchedFiles() {
+		st := fileStat{}
+		if info, err := os.Stat(f); err == nil {
+			st = fileStat{info.ModTime(), info.Size()}
+		}
+
+		if prev, ok := watchedFileStats[f]; ok && prev != st {
+			changed = true
+		}
+
+		watchedFileStats[f] = st
+	}
+	return
+}
+
+func watchConfigFiles() {
+	if *configWatchInterval <= 0 || len(watchedFiles()) == 0 {
+		return
+	}
+
+	filesChanged() // initial state
+
+	for range time.Tick(*configWatchInterval) {
+		if filesChanged() {
+			reloadAccess("files changed")
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"net"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+var (
+	shutdownTimeout = flag.Duration("shutdown-timeout", 30*time.Second, "On SIGTERM/SIGINT, time given to running syncs to finish before cancelling them")
+
+	// time given to cancelled syncs to stop
+	shutdownCancelTimeout = 10 * time.Second
+
+	mainListener net.Listener
+	activeConns  = sync.WaitGroup{}
+	shuttingDown int32
+	shutdownOnce = sync.Once{}
+)
+
+func isShuttingDown() bool {
+	return atomic.LoadInt32(&shuttingDown) != 0
+}
+
+// gracefulShutdown stops accepting connections, drains the running syncs, then exits.
+func gracefulShutdown(reason string) {
+	shutdownOnce.Do(func() {
+		atomic.StoreInt32(&shuttingDown, 1)
+
+		log.Printf("%s: shutting down (timeout: %v)", reason, *shutdownTimeout)
+
+		if mainListener != nil {
+			mainListener.Close()
+		}
+
+		if grpcServer != nil {
+			// stops accepting calls; running syncs are tracked in activeConns
+			go grpcServer.GracefulStop()
+		}
+
+		if !waitGroup(&activeConns, *shutdownTimeout) {
+			log.Print("shutdown: timed out waiting for syncs, cancelling them")
+			cancelAllConns()
+
+			if !waitGroup(&activeConns, shutdownCancelTimeout) {
+				log.Print("shutdown: WARNING: some connections are still running")
+			}
+		}
+
+		shutdownHTTP()
+
+		// indexings can't be cancelled, and the store can't be closed under them
+		if stopIndexers(*shutdownTimeout) {
+			closeStore()
+		} else {
+			log.Print("shutdown: WARNING: timed out waiting for indexings, not closing the store")
+		}
+
+		if kafka != nil {
+			if err := kafka.Close(); err != nil {
+				log.Print("shutdown: failed to close Kafka client: ", err)
+			}
+		}
+
+		log.Print("shutdown: done")
+		os.Exit(0)
+	})
+}
+
+// waitGroup waits for the group to finish, returning false on timeout.
+func waitGroup(wg *sync.WaitGroup, timeout time.Duration) bool {
+	done := make(chan bool)
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return true
+	case <-time.After(timeout):
+		return false
+	}
+}
+
+func cancelAllConns() {
+	connStatusesMutex.Lock()
+	defer connStatusesMutex.Unlock()
+
+	for _, cs := range connStatuses {
+		if cs.EndTime.IsZero() {
+			cs.Cancel()
+		}
+	}
+}
+
+func shutdownHTTP() {
+	if httpServer == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownCancelTimeout)
+	defer cancel()
+
+	if err := httpServer.Shutdown(ctx); err != nil {
+		log.Print("shutdown: failed to stop the HTTP server: ", err)
+	}
+}
+
+func closeStore() {
+	if !hasStore {
+		return
+	}
+
+	if err := db.Sync(); err != nil {
+		log.Print("shutdown: failed to sync store: ", err)
+	}
+
+	if err := db.Close(); err != nil {
+		log.Print("shutdown: failed to close store: ", err)
+	}
+}
@@ -0,0 +1,173 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	restful "github.com/emicklei/go-restful"
+
+	"github.com/mcluseau/sync2kafka/client"
+)
+
+// syncAPI is the HTTP alternative to the TCP protocol: the values are the request's body,
+// the init info is given by the query parameters and the sync token by the Authorization header.
+type syncAPI struct{}
+
+func (a *syncAPI) Register() {
+	// separate web service, as it uses the sync tokens, not the HTTP API token
+	ws := &restful.WebService{}
+	ws.Path("/topics/{topic}/sync")
+	ws.Produces(restful.MIME_JSON)
+
+	ws.Route(ws.POST("").To(a.Sync).
+		Doc("Synchronize the topic with the values in the body (JSON lines, in the given format)").
+		Consumes("*/*").
+		Param(ws.PathParameter("topic", "Name of the topic")).
+		Param(ws.HeaderParameter("Authorization", "Bearer sync token")).
+		Param(ws.QueryParameter("format", "Values format: json (default), binary or raw")).
+		Param(ws.QueryParameter("mode", "Sync mode: full (default) or delta")).
+		Param(ws.QueryParameter("doDelete", "Delete the keys not in the values (full mode), allow delete records (delta mode)").DataType("boolean")).
+		Param(ws.QueryParameter("force", "Ignore the server's limits on the number of deleted keys").DataType("boolean")).
+		Param(ws.QueryParameter("header", "Header added to the produced messages (name=value, repeatable)").AllowMultiple(true)).
+		Param(ws.QueryParameter("dryRun", "Only compute the changes").DataType("boolean")).
+		Param(ws.QueryParameter("dryRunSampleSize", "Number of changed keys to report in dry run mode").DataType("integer")).
+		Param(ws.QueryParameter("waitForLock", "Wait for the topic's lock instead of failing").DataType("boolean")).
+		Param(ws.QueryParameter("waitForLockTimeout", "Maximum time to wait for the lock, in seconds").DataType("integer")).
+		Writes(SyncResult{}))
+
+	restful.Add(ws)
+}
+
+func (a *syncAPI) Sync(req *restful.Request, res *restful.Response) {
+	if isShuttingDown() {
+		res.WriteErrorString(http.StatusServiceUnavailable, "shutting down")
+		return
+	}
+
+	activeConns.Add(1)
+	defer activeConns.Done()
+
+	status := newConnStatus(req.Request.RemoteAddr)
+	defer status.Finished()
+
+	logPrefix := fmt.Sprintf("%s from %v (HTTP): ", status.ID, status.Remote)
+
+	log.Print(logPrefix, "new sync request")
+
+	activeConnections.Inc()
+	defer activeConnections.Dec()
+
+	init, err := a.initInfo(req)
+	if err != nil {
+		err = newSyncError(client.ErrorCodeBadRequest, "%v", err)
+		log.Print(logPrefix, "rejecting: ", err)
+		a.respond(res, errorResult(err))
+		return
+	}
+
+	session := &syncSession{
+		status:    status,
+		init:      init,
+		logPrefix: logPrefix,
+		readKVs:   kvReader(init.Format, json.NewDecoder(req.Request.Body), req.Request.Body),
+		endOnEOF:  true,
+	}
+
+	if req.Request.ProtoMajor == 1 {
+		// HTTP/1 body reads are only interrupted by the connection's deadline
+		if conn, ok := req.Request.Context().Value(httpConnKey{}).(net.Conn); ok {
+			session.interrupt = func() { conn.SetReadDeadline(time.Now()) }
+		}
+	} else {
+		session.interrupt = func() { req.Request.Body.Close() }
+	}
+
+	a.respond(res, session.run())
+}
+
+func (a *syncAPI) initInfo(req *restful.Request) (init *SyncInitInfo, err error) {
+	init = &SyncInitInfo{
+		Format: req.QueryParameter("format"),
+		Mode:   req.QueryParameter("mode"),
+		Topic:  req.PathParameter("topic"),
+	}
+
+	if len(init.Format) == 0 {
+		init.Format = "json"
+	}
+
+	if hdr := req.HeaderParameter("Authorization"); strings.HasPrefix(hdr, bearerHdr) {
+		init.Token = hdr[len(bearerHdr):]
+	}
+
+	boolParam := func(name string, v *bool) {
+		if s := req.QueryParameter(name); err == nil && len(s) != 0 {
+			if *v, err = strconv.ParseBool(s); err != nil {
+				err = fmt.Errorf("invalid %s: %q", name, s)
+			}
+		}
+	}
+
+	intParam := func(name string, v *int) {
+		if s := req.QueryParameter(name); err == nil && len(s) != 0 {
+			if *v, err = strconv.Atoi(s); err != nil {
+				err = fmt.Errorf("invalid %s: %q", name, s)
+			}
+		}
+	}
+
+	for _, header := range req.QueryParameters("header") {
+		idx := strings.IndexByte(header, '=')
+		if idx < 0 {
+			return nil, fmt.Errorf("invalid header: %q", header)
+		}
+
+		if init.Headers == nil {
+			init.Headers = map[string]string{}
+		}
+		init.Headers[header[:idx]] = header[idx+1:]
+	}
+
+	boolParam("doDelete", &init.DoDelete)
+	boolParam("force", &init.Force)
+	boolParam("dryRun", &init.DryRun)
+	intParam("dryRunSampleSize", &init.DryRunSampleSize)
+	boolParam("waitForLock", &init.WaitForLock)
+	intParam("waitForLockTimeout", &init.WaitForLockTimeout)
+
+	return
+}
+
+func (a *syncAPI) respond(res *restful.Response, result SyncResult) {
+	res.WriteHeaderAndEntity(syncResultHTTPStatus(result), result)
+}
+
+// syncResultHTTPStatus maps a sync result to an HTTP status.
+func syncResultHTTPStatus(result SyncResult) int {
+	if result.OK {
+		return http.StatusOK
+	}
+
+	switch result.ErrorCode {
+	case client.ErrorCodeBadRequest, client.ErrorCodeUnknownFormat, client.ErrorCodeReadFailed:
+		return http.StatusBadRequest
+	case client.ErrorCodeUnauthorized:
+		return http.StatusUnauthorized
+	case client.ErrorCodeForbidden, client.ErrorCodeTopicNotAllowed:
+		return http.StatusForbidden
+	case client.ErrorCodeTopicNotFound:
+		return http.StatusNotFound
+	case client.ErrorCodeTopicLocked:
+		return http.StatusConflict
+	case client.ErrorCodeTopicNotCompacted, client.ErrorCodeTooManyDeletes:
+		return http.StatusPreconditionFailed
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/mcluseau/sync2kafka/client"
+)
+
+func TestSyncResultHTTPStatus(t *testing.T) {
+	for _, tc := range []struct {
+		result SyncResult
+		status int
+	}{
+		{SyncResult{OK: true}, http.StatusOK},
+		{SyncResult{ErrorCode: client.ErrorCodeBadRequest}, http.StatusBadRequest},
+		{SyncResult{ErrorCode: client.ErrorCodeUnknownFormat}, http.StatusBadRequest},
+		{SyncResult{ErrorCode: client.ErrorCodeReadFailed}, http.StatusBadRequest},
+		{SyncResult{ErrorCode: client.ErrorCodeUnauthorized}, http.StatusUnauthorized},
+		{SyncResult{ErrorCode: client.ErrorCodeForbidden}, http.StatusForbidden},
+		{SyncResult{ErrorCode: client.ErrorCodeTopicNotAllowed}, http.StatusForbidden},
+		{SyncResult{ErrorCode: client.ErrorCodeTopicNotFound}, http.StatusNotFound},
+		{SyncResult{ErrorCode: client.ErrorCodeTopicLocked}, http.StatusConflict},
+		{SyncResult{ErrorCode: client.ErrorCodeTopicNotCompacted}, http.StatusPreconditionFailed},
+		{SyncResult{ErrorCode: client.ErrorCodeTooManyDeletes}, http.StatusPreconditionFailed},
+		{SyncResult{ErrorCode: client.ErrorCodeSyncFailed}, http.StatusInternalServerError},
+		{SyncResult{ErrorCode: client.ErrorCodeCancelled}, http.StatusInternalServerError},
+		{SyncResult{ErrorCode: client.ErrorCodeInternal}, http.StatusInternalServerError},
+		{SyncResult{ErrorCode: "unknown"}, http.StatusInternalServerError},
+		{SyncResult{}, http.StatusInternalServerError},
+	} {
+		if status := syncResultHTTPStatus(tc.result); status != tc.status {
+			t.Errorf("%q: got status %d, expected %d", tc.result.ErrorCode, status, tc.status)
+		}
+	}
+}
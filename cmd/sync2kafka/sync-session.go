@@ -0,0 +1,341 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/mcluseau/sync2kafka/client"
+)
+
+// syncSession is a sync requested by a client, whatever the transport.
+type syncSession struct {
+	status    *ConnStatus
+	init      *SyncInitInfo
+	logPrefix string
+
+	// readKVs reads the values from the client; nil if the format is not supported
+	readKVs func(out chan KeyValue, status *ConnStatus) error
+
+	// endOnEOF accepts the end of the stream as the end of the transfer
+	endOnEOF bool
+	// accepted is called when the sync is accepted, before reading values (optional); it may replace readKVs
+	accepted func() error
+	// interrupt unblocks reads when the sync is cancelled (optional)
+	interrupt func()
+}
+
+// deleteRecordsDenied returns why the sync can't send delete records, nil if it can.
+//
+// In delta mode, they require DoDelete; in full mode, they don't (to delete some keys without
+// the unseen keys) but still require the permission to delete.
+func deleteRecordsDenied(init *SyncInitInfo, topic, mode string) error {
+	if mode == "delta" {
+		if !init.DoDelete {
+			return newSyncError(client.ErrorCodeForbidden, "delete records require doDelete in delta mode")
+		}
+		return nil
+	}
+
+	if init.DoDelete || init.DryRun {
+		// already checked, or nothing will be deleted
+		return nil
+	}
+
+	if _, err := authorize(init.Token, topic, true); err != nil {
+		return err
+	}
+
+	if getTopicConfig(topic).DeletePolicy == "deny" {
+		return newSyncError(client.ErrorCodeForbidden, "deletions not allowed on this topic")
+	}
+
+	return nil
+}
+
+// syncFailure returns the error to report for a failed sync, keeping its code if it has one.
+func syncFailure(err error) error {
+	if errors.As(err, new(*syncError)) {
+		return err
+	}
+	return newSyncError(client.ErrorCodeSyncFailed, "sync failed: %v", err)
+}
+
+// run processes the sync, returning the result to send to the client.
+func (s *syncSession) run() (result SyncResult) {
+	init, status := s.init, s.status
+	logPrefix := s.logPrefix
+
+	defer func() {
+		result.ConnectionID = status.ID
+	}()
+
+	reject := func(err error) SyncResult {
+		log.Print(logPrefix, "rejecting: ", err)
+		return errorResult(err)
+	}
+
+	topic := *targetTopic
+	if len(init.Topic) != 0 {
+		topic = init.Topic
+	}
+
+	if len(topic) == 0 {
+		return reject(newSyncError(client.ErrorCodeBadRequest, "no topic specified and no default topic"))
+	}
+
+	if s.readKVs == nil {
+		return reject(newSyncError(client.ErrorCodeUnknownFormat, "unknown format %q", init.Format))
+	}
+
+	if err := checkClientHeaders(init.Headers); err != nil {
+		return reject(newSyncError(client.ErrorCodeBadRequest, "%v", err))
+	}
+
+	mode := init.Mode
+	switch mode {
+	case "":
+		mode = "full"
+	case "full", "delta":
+	default:
+		return reject(newSyncError(client.ErrorCodeBadRequest, "unknown mode %q", init.Mode))
+	}
+
+	// a dry run never deletes anything
+	identity, err := authorize(init.Token, topic, init.DoDelete && !init.DryRun)
+	if len(identity) != 0 {
+		logPrefix += fmt.Sprintf("as %q: ", identity)
+	}
+
+	if err != nil {
+		return reject(fmt.Errorf("topic %q: %w", topic, err))
+	}
+
+	if !isTopicAllowed(topic) {
+		return reject(newSyncError(client.ErrorCodeTopicNotAllowed, "topic %q not allowed", topic))
+	}
+
+	if err := getTopicConfig(topic).check(identity, init); err != nil {
+		return reject(fmt.Errorf("topic %q: %w", topic, err))
+	}
+
+	status.deleteDenied = deleteRecordsDenied(init, topic, mode)
+
+	// set before the lock so queued syncs can be found (and cancelled) by topic
+	status.TargetTopic = topic
+
+	onLockLost := func() {
+		log.Print(logPrefix, "topic lock lost, cancelling")
+		status.Cancel()
+	}
+
+	if init.WaitForLock {
+		timeout := *maxLockWait
+		if t := time.Duration(init.WaitForLockTimeout) * time.Second; t > 0 && t < timeout {
+			timeout = t
+		}
+
+		status.Status = fmt.Sprintf("waiting for topic %q lock", topic)
+		log.Printf("%swaiting for topic %q lock (timeout: %v)", logPrefix, topic, timeout)
+
+		if !waitLockTopic(topic, timeout, status.cancel, func(position int) { status.QueuePosition = position }, onLockLost) {
+			if status.Cancelled() {
+				return reject(errCancelled)
+			}
+			return reject(newSyncError(client.ErrorCodeTopicLocked, "timed out waiting for topic %q lock", topic))
+		}
+
+	} else if !lockTopic(topic, onLockLost) {
+		return reject(newSyncError(client.ErrorCodeTopicLocked, "topic %q already locked", topic))
+	}
+	defer unlockTopic(topic)
+	status.lockHeld = true
+
+	// a dry run never creates anything
+	if err := ensureTopic(topic, *createTopics && !init.DryRun); err != nil {
+		if *compactionCheck == "warn" && errorResult(err).ErrorCode == client.ErrorCodeTopicNotCompacted {
+			log.Print(logPrefix, "WARNING: ", err)
+		} else {
+			return reject(err)
+		}
+	}
+
+	log.Printf("%saccepting topic %q", logPrefix, topic)
+	syncsStarted.WithLabelValues(topic).Inc()
+	status.Identity = identity
+	status.DryRun = init.DryRun
+	status.Mode = mode
+
+	record := &SyncRecord{
+		ID:        status.ID,
+		Topic:     topic,
+		Remote:    status.Remote,
+		Identity:  identity,
+		DryRun:    init.DryRun,
+		DoDelete:  init.DoDelete,
+		Mode:      mode,
+		StartTime: time.Now(),
+	}
+
+	defer func() {
+		record.EndTime = time.Now()
+		record.ItemsRead = status.ItemsRead
+		record.Stats = result.Stats
+		record.OK = result.OK
+		record.ErrorCode = result.ErrorCode
+		record.Error = result.Error
+
+		if !record.OK && len(record.ErrorCode) == 0 {
+			// no result sent: connection lost or panic
+			record.ErrorCode = client.ErrorCodeInternal
+			record.Error = "interrupted"
+		}
+
+		recordSync(record)
+	}()
+	logPrefix += fmt.Sprintf("to topic %q: ", topic)
+
+	if init.DryRun {
+		logPrefix += "dry run: "
+	}
+
+	if mode == "delta" {
+		logPrefix += "delta: "
+	}
+
+	if s.accepted != nil {
+		if err := s.accepted(); err != nil {
+			log.Print(logPrefix, "failed to send handshake: ", err)
+			return errorResult(newSyncError(client.ErrorCodeInternal, "failed to send handshake: %v", err))
+		}
+	}
+
+	defer status.Cancel()
+
+	if s.interrupt != nil {
+		done := make(chan bool)
+		defer close(done) // before the status.Cancel() above
+
+		go func() {
+			// interrupt reads when cancelled, but not when the session ends
+			select {
+			case <-status.cancel:
+				s.interrupt()
+			case <-done:
+			}
+		}()
+	}
+
+	wg := sync.WaitGroup{}
+	wg.Add(1)
+
+	var syncErr error
+
+	kvSource := make(chan KeyValue, kvBufferSize)
+
+	spec := &syncSpec{
+		Source:      kvSource,
+		TargetTopic: topic,
+		DoDelete:    init.DoDelete,
+		Cancel:      status.cancel,
+		LogPrefix:   logPrefix,
+		DryRun:      init.DryRun,
+		SampleSize:  init.DryRunSampleSize,
+		Delta:       mode == "delta",
+	}
+
+	source := identity
+	if len(source) == 0 {
+		source = status.Remote
+	}
+	spec.Headers = syncHeaders(status.ID, source, init.Headers)
+
+	if len(init.Headers) != 0 && !messageHeaderEnabled("client") {
+		log.Print(logPrefix, "ignoring the client's headers (not enabled)")
+	}
+
+	if init.DoDelete && !spec.Delta {
+		spec.DeleteLimits = getDeleteLimits(topic)
+
+		if init.Force && !spec.DeleteLimits.none() {
+			log.Print(logPrefix, "forced: ignoring the deletion limits (", spec.DeleteLimits, ")")
+			spec.DeleteLimits = deleteLimits{}
+		}
+	}
+
+	go func() {
+		defer wg.Done()
+		status.SyncStats, syncErr = spec.sync()
+
+		if syncErr != nil {
+			// nothing consumes the values anymore
+			status.Cancel()
+		}
+	}()
+
+	status.Status = "reading data"
+
+	err = s.readKVs(kvSource, status)
+	if err == io.EOF && s.endOnEOF {
+		err = nil
+	}
+
+	if err != nil {
+		if status.Cancelled() {
+			err = errCancelled
+		} else if !errors.As(err, new(*syncError)) {
+			err = newSyncError(client.ErrorCodeReadFailed, "failed to read values: %v", err)
+		}
+
+		// stop the sync and wait for it before releasing the topic
+		status.Cancel()
+		wg.Wait()
+
+		if syncErr != nil {
+			err = syncFailure(syncErr)
+		}
+
+		recordSyncEnd(topic, status.ItemsRead, nil, init.DryRun, err)
+		return reject(err)
+	}
+
+	log.Print(logPrefix, "finished reading values")
+	close(kvSource)
+
+	status.Status = "finializing"
+	wg.Wait()
+
+	if syncErr == nil && status.Cancelled() {
+		syncErr = errCancelled
+	}
+
+	if status.SyncStats != nil {
+		log.Print(logPrefix, "sync stats:\n", status.SyncStats.LogString())
+	}
+
+	recordSyncEnd(topic, status.ItemsRead, status.SyncStats, init.DryRun, syncErr)
+
+	if syncErr == errCancelled {
+		log.Print(logPrefix, "sync cancelled")
+		result = errorResult(syncErr)
+	} else if syncErr != nil {
+		log.Print(logPrefix, "sync failed: ", syncErr)
+		result = errorResult(syncFailure(syncErr))
+	} else {
+		result = SyncResult{OK: true}
+	}
+
+	if status.SyncStats != nil {
+		result.Stats = resultStats(status.ItemsRead, status.SyncStats)
+	}
+
+	if init.DryRun && syncErr == nil {
+		result.DryRun = dryRunResult(init.Format, spec.Sample)
+		result.DryRun.Warning = spec.DeleteLimitWarning
+	}
+
+	return
+}
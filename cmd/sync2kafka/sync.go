@@ -1,27 +1,51 @@
 package main
 
 import (
+	"crypto/sha256"
+	"hash/fnv"
 	"log"
+	"time"
 
+	"github.com/Shopify/sarama"
 	diff "github.com/mcluseau/go-diff"
 	"github.com/mcluseau/go-diff/boltindex"
 	kafkasync "github.com/mcluseau/kafka-sync"
 )
 
 type syncSpec struct {
+	// Source are the values to sync, a nil value being an explicit delete
 	Source      chan KeyValue
 	TargetTopic string
 	DoDelete    bool
 	Cancel      chan bool
+	LogPrefix   string
+
+	// Delta applies the source's upserts and deletes instead of a full dataset.
+	Delta bool
+
+	// DeleteLimits limit the deletion of unseen keys (DoDelete).
+	DeleteLimits deleteLimits
+
+	// Headers are added to each message (see syncHeaders).
+	Headers []sarama.RecordHeader
+
+	// DryRun only computes the changes, recording up to SampleSize of them in Sample.
+	DryRun     bool
+	SampleSize int
+	Sample     []diff.Change
+
+	// DeleteLimitWarning is why the sync would fail on its deletion limits (dry run only).
+	DeleteLimitWarning string
 }
 
 func (spec *syncSpec) sync() (stats *SyncStats, err error) {
 	syncer := kafkasync.New(spec.TargetTopic)
+	syncer.Partition = getTopicConfig(spec.TargetTopic).Partition
 
 	var index diff.Index
 	if hasStore {
 		// use the local store
-		index, err = boltindex.New(db, []byte(spec.TargetTopic), spec.DoDelete)
+		index, err = boltindex.New(db, indexBucket(spec.TargetTopic, syncer.Partition), spec.DoDelete && !spec.Delta)
 	} else {
 		// in memory index; simple but slower on big datasets, as it requires reindexing the topic each time
 		index = diff.NewIndex(false)
@@ -31,19 +55,25 @@ func (spec *syncSpec) sync() (stats *SyncStats, err error) {
 		return
 	}
 
-	log.Print("index created")
+	log.Print(spec.LogPrefix, "index created")
 	defer func() {
-		log.Print("index cleanup")
+		log.Print(spec.LogPrefix, "index cleanup")
 		if err := index.Cleanup(); err != nil {
-			log.Print("WARN: index cleanup failed: ", err)
+			log.Print(spec.LogPrefix, "WARN: index cleanup failed: ", err)
 		}
-		log.Print("index cleaned-up")
+		log.Print(spec.LogPrefix, "index cleaned-up")
 	}()
 
-	stats, err = syncer.SyncWithIndex(kafka, spec.Source, index, spec.Cancel)
+	if spec.Delta {
+		stats, err = spec.syncDelta(syncer, index)
+	} else if spec.DryRun {
+		stats, err = spec.dryRun(syncer, index)
+	} else {
+		stats, err = spec.syncFull(syncer, index)
+	}
 
 	if hasStore {
-		if err != nil {
+		if err == nil {
 			err = db.Sync()
 		}
 
@@ -52,3 +82,360 @@ func (spec *syncSpec) sync() (stats *SyncStats, err error) {
 
 	return
 }
+
+// syncFull is kafkasync's SyncWithIndex, with the source's explicit deletes and the message headers.
+func (spec *syncSpec) syncFull(syncer kafkasync.Syncer, index diff.Index) (stats *SyncStats, err error) {
+	stats = kafkasync.NewStats()
+
+	msgCount, err := syncer.IndexTopic(kafka, index)
+	if err != nil {
+		return
+	}
+
+	stats.MessagesInTopic = msgCount
+	stats.ReadTopicDuration = stats.Elapsed()
+
+	send, finish, err := spec.setupProducer(syncer, stats)
+	if err != nil {
+		return
+	}
+
+	startSyncTime := time.Now()
+
+	changes := make(chan diff.Change, 10)
+	diffErr := make(chan error, 1)
+	go func() {
+		defer close(changes)
+		diffErr <- spec.diff(index, changes)
+	}()
+
+	applyChanges(changes, send, stats, spec.Cancel)
+	finish()
+
+	// the diff also stops when applyChanges is cancelled
+	err = <-diffErr
+
+	stats.SyncDuration = time.Since(startSyncTime)
+	stats.TotalDuration = stats.Elapsed()
+
+	return
+}
+
+// diff is diff.DiffStreamIndex, also streaming the explicit deletes (nil values) of existing keys.
+// It stops when the sync is cancelled.
+func (spec *syncSpec) diff(index diff.Index, changes chan diff.Change) error {
+	send := func(change diff.Change) bool {
+		select {
+		case changes <- change:
+			return true
+		case <-spec.Cancel:
+			return false
+		}
+	}
+
+	// keys of the source in the index, the total of the deletion limits
+	var existing uint64
+
+	// the keys already counted as existing, so repeated source keys aren't (by 64 bits hash, as a
+	// collision only undercounts the total)
+	var existingKeys map[uint64]bool
+	if spec.DoDelete && !spec.DeleteLimits.none() {
+		existingKeys = map[uint64]bool{}
+	}
+
+	countExisting := func(key []byte) {
+		if existingKeys != nil {
+			h := fnv.New64a()
+			h.Write(key)
+
+			keyHash := h.Sum64()
+			if existingKeys[keyHash] {
+				return
+			}
+			existingKeys[keyHash] = true
+		}
+
+		existing++
+	}
+
+loop:
+	for {
+		var (
+			kv KeyValue
+			ok bool
+		)
+
+		select {
+		case <-spec.Cancel:
+			return nil
+
+		case kv, ok = <-spec.Source:
+			if !ok {
+				break loop
+			}
+		}
+
+		if kv.Value == nil {
+			// also marks the key as seen, so it's not deleted again with DoDelete
+			cmp, err := index.Compare(KeyValue{Key: kv.Key, Value: []byte{}})
+			if err != nil {
+				return err
+			}
+
+			if cmp != diff.MissingKey {
+				countExisting(kv.Key)
+				if !send(diff.Change{Type: diff.Deleted, Key: kv.Key}) {
+					return nil
+				}
+			}
+			continue
+		}
+
+		cmp, err := index.Compare(kv)
+		if err != nil {
+			return err
+		}
+
+		if cmp != diff.MissingKey {
+			countExisting(kv.Key)
+		}
+
+		var change diff.Change
+		switch cmp {
+		case diff.MissingKey:
+			change = diff.Change{Type: diff.Created, Key: kv.Key, Value: kv.Value}
+		case diff.ModifiedKey:
+			change = diff.Change{Type: diff.Modified, Key: kv.Key, Value: kv.Value}
+		case diff.UnchangedKey:
+			change = diff.Change{Type: diff.Unchanged, Key: kv.Key}
+		}
+
+		if !send(change) {
+			return nil
+		}
+	}
+
+	if !spec.DoDelete {
+		// the in memory index always tracks unseen keys
+		return nil
+	}
+
+	keysNotSeen := index.KeysNotSeen()
+	if keysNotSeen == nil {
+		return nil
+	}
+
+	limits := spec.DeleteLimits
+	if limits.none() || spec.DryRun {
+		count := uint64(0)
+		for key := range keysNotSeen {
+			count++
+			if !send(diff.Change{Type: diff.Deleted, Key: key}) {
+				// let the index finish its iteration (and its transaction)
+				for range keysNotSeen {
+				}
+				return nil
+			}
+		}
+
+		// a dry run reports all the deletions, and the limits as a warning
+		if err := limits.check(count, existing+count); err != nil {
+			spec.DeleteLimitWarning = err.Error()
+		}
+		return nil
+	}
+
+	// hold the deletions until the limits are checked (no need to keep more than the count limit)
+	deletes := make([][]byte, 0)
+	count := uint64(0)
+	for key := range keysNotSeen {
+		count++
+		if limits.count == 0 || count <= uint64(limits.count) {
+			deletes = append(deletes, key)
+		}
+	}
+
+	if err := limits.check(count, existing+count); err != nil {
+		return err
+	}
+
+	for _, key := range deletes {
+		if !send(diff.Change{Type: diff.Deleted, Key: key}) {
+			break
+		}
+	}
+
+	return nil
+}
+
+func (spec *syncSpec) dryRun(syncer kafkasync.Syncer, index diff.Index) (stats *SyncStats, err error) {
+	stats = kafkasync.NewStats()
+
+	msgCount, err := syncer.IndexTopic(kafka, index)
+	if err != nil {
+		return
+	}
+
+	stats.MessagesInTopic = msgCount
+	stats.ReadTopicDuration = stats.Elapsed()
+
+	startSyncTime := time.Now()
+
+	changes := make(chan diff.Change, 10)
+	diffErr := make(chan error, 1)
+	go func() {
+		defer close(changes)
+		diffErr <- spec.diff(index, changes)
+	}()
+
+	for change := range changes {
+		switch change.Type {
+		case diff.Unchanged:
+			stats.Unchanged++
+			stats.Count++
+			continue
+
+		case diff.Created:
+			stats.Created++
+			stats.Count++
+
+		case diff.Modified:
+			stats.Modified++
+			stats.Count++
+
+		case diff.Deleted:
+			stats.Deleted++
+		}
+
+		spec.recordSample(change.Type, change.Key)
+	}
+
+	err = <-diffErr
+
+	stats.SyncDuration = time.Since(startSyncTime)
+	stats.TotalDuration = stats.Elapsed()
+
+	return
+}
+
+func (spec *syncSpec) recordSample(changeType diff.ChangeType, key []byte) {
+	if len(spec.Sample) < spec.SampleSize {
+		spec.Sample = append(spec.Sample, diff.Change{Type: changeType, Key: key})
+	}
+}
+
+// deltaValue is the state of a key changed during a delta sync.
+type deltaValue struct {
+	deleted bool
+	hash    [sha256.Size]byte
+}
+
+// syncDelta sends the source's changes, skipping the values already in the topic.
+//
+// As for full syncs, the index is updated from the topic after the sync, so it only records
+// what Kafka accepted; the keys changed during the sync are tracked in memory meanwhile.
+func (spec *syncSpec) syncDelta(syncer kafkasync.Syncer, index diff.Index) (stats *SyncStats, err error) {
+	stats = kafkasync.NewStats()
+
+	msgCount, err := syncer.IndexTopic(kafka, index)
+	if err != nil {
+		return
+	}
+
+	stats.MessagesInTopic = msgCount
+	stats.ReadTopicDuration = stats.Elapsed()
+
+	startSyncTime := time.Now()
+
+	send, finish := func(diff.Change) {}, func() {}
+	if !spec.DryRun {
+		if send, finish, err = spec.setupProducer(syncer, stats); err != nil {
+			return
+		}
+	}
+
+	changed := map[string]deltaValue{}
+
+loop:
+	for {
+		var (
+			kv KeyValue
+			ok bool
+		)
+
+		select {
+		case <-spec.Cancel:
+			break loop
+
+		case kv, ok = <-spec.Source:
+			if !ok {
+				break loop
+			}
+		}
+
+		deleted := kv.Value == nil
+
+		var cmp diff.CompareResult
+		cmp, err = spec.compareDelta(kv, changed, index)
+		if err != nil {
+			break
+		}
+
+		var changeType diff.ChangeType
+		switch {
+		case deleted && cmp == diff.MissingKey, !deleted && cmp == diff.UnchangedKey:
+			stats.Unchanged++
+			continue
+
+		case deleted:
+			changeType = diff.Deleted
+			stats.Deleted++
+
+		case cmp == diff.MissingKey:
+			changeType = diff.Created
+			stats.Created++
+
+		default:
+			changeType = diff.Modified
+			stats.Modified++
+		}
+
+		send(diff.Change{Type: changeType, Key: kv.Key, Value: kv.Value})
+
+		changed[string(kv.Key)] = deltaValue{deleted: deleted, hash: sha256.Sum256(kv.Value)}
+
+		if spec.DryRun {
+			spec.recordSample(changeType, kv.Key)
+		}
+	}
+
+	finish()
+
+	stats.SyncDuration = time.Since(startSyncTime)
+	stats.TotalDuration = stats.Elapsed()
+
+	return
+}
+
+// compareDelta compares a value with the key's last change in this sync, or with the index.
+// A delete record (nil value) compares as missing if the key is already deleted.
+func (spec *syncSpec) compareDelta(kv KeyValue, changed map[string]deltaValue, index diff.Index) (diff.CompareResult, error) {
+	if prev, ok := changed[string(kv.Key)]; ok {
+		switch {
+		case prev.deleted:
+			return diff.MissingKey, nil
+		case prev.hash == sha256.Sum256(kv.Value):
+			return diff.UnchangedKey, nil
+		default:
+			return diff.ModifiedKey, nil
+		}
+	}
+
+	value := kv.Value
+	if value == nil {
+		// the index doesn't accept nil values, and only the key's presence matters
+		value = []byte{}
+	}
+
+	return index.Compare(KeyValue{Key: kv.Key, Value: value})
+}
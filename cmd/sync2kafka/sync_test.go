@@ -0,0 +1,197 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Shopify/sarama"
+	"github.com/boltdb/bolt"
+	diff "github.com/mcluseau/go-diff"
+	"github.com/mcluseau/go-diff/boltindex"
+
+	"github.com/mcluseau/sync2kafka/client"
+)
+
+func TestDiffStopsOnCancel(t *testing.T) {
+	for _, doDelete := range []bool{false, true} {
+		index := newTestIndex("old1", "old2")
+
+		source := make(chan KeyValue, 2)
+		source <- KeyValue{Key: []byte("new"), Value: []byte("v")}
+		close(source)
+
+		spec := &syncSpec{
+			Source:   source,
+			DoDelete: doDelete,
+			Cancel:   make(chan bool),
+		}
+
+		// nobody reads the changes
+		changes := make(chan diff.Change)
+
+		done := make(chan error, 1)
+		go func() { done <- spec.diff(index, changes) }()
+
+		time.Sleep(10 * time.Millisecond)
+		close(spec.Cancel)
+
+		select {
+		case err := <-done:
+			if err != nil {
+				t.Errorf("doDelete=%v: unexpected error: %v", doDelete, err)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("doDelete=%v: diff still blocked after cancel", doDelete)
+		}
+	}
+}
+
+// newTestIndex returns an in memory index of the keys.
+func newTestIndex(keys ...string) diff.Index {
+	kvs := make(chan KeyValue, len(keys))
+	for _, key := range keys {
+		kvs <- KeyValue{Key: []byte(key), Value: []byte("v")}
+	}
+	close(kvs)
+
+	index := diff.NewIndex(false)
+	index.Index(kvs, nil)
+	return index
+}
+
+func TestDiffDeleteLimitsRepeatedKeys(t *testing.T) {
+	// key0 sent 3 times is still 1 of the 3 keys, so deleting the 2 others exceeds 50%
+	source := make(chan KeyValue, 3)
+	for i := 0; i < 3; i++ {
+		source <- KeyValue{Key: []byte("key0"), Value: []byte("v")}
+	}
+	close(source)
+
+	spec := &syncSpec{
+		Source:       source,
+		DoDelete:     true,
+		Cancel:       make(chan bool),
+		DeleteLimits: deleteLimits{percent: 50},
+	}
+
+	changes := make(chan diff.Change, 10)
+
+	err := spec.diff(newTestIndex("key0", "key1", "key2"), changes)
+	if resErr := errorResult(err).Err(); !errors.Is(resErr, client.ErrTooManyDeletes) {
+		t.Errorf("got %v, expected too many deletes", err)
+	}
+}
+
+func TestSyncDeleteLimitsWithStore(t *testing.T) {
+	// the topics hold 3 keys, and the syncs only send 1 of them
+	const topicMessages = 3
+
+	broker := sarama.NewMockBroker(t, 1)
+	defer broker.Close()
+
+	metadata := sarama.NewMockMetadataResponse(t).SetBroker(broker.Addr(), broker.BrokerID())
+	offsets := sarama.NewMockOffsetResponse(t)
+	for _, topic := range []string{"sync", "dry-run"} {
+		metadata.SetLeader(topic, 0, broker.BrokerID())
+		offsets.SetOffset(topic, 0, sarama.OffsetOldest, 0).
+			SetOffset(topic, 0, sarama.OffsetNewest, topicMessages)
+	}
+
+	broker.SetHandlerByMap(map[string]sarama.MockResponse{
+		"MetadataRequest": metadata,
+		"OffsetRequest":   offsets,
+	})
+
+	defer func(prev sarama.Client) { kafka = prev }(kafka)
+	defer func(prev *bolt.DB) { db = prev }(db)
+	defer func(prev bool) { hasStore = prev }(hasStore)
+	defer access.Store(loadedAccess())
+	defer atomic.StoreInt32(&shuttingDown, atomic.LoadInt32(&shuttingDown))
+
+	conf := sarama.NewConfig()
+	conf.Producer.Return.Successes = true
+
+	var err error
+	if kafka, err = sarama.NewClient([]string{broker.Addr()}, conf); err != nil {
+		t.Fatal(err)
+	}
+	defer kafka.Close()
+
+	if db, err = bolt.Open(filepath.Join(t.TempDir(), "store"), 0644, nil); err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	hasStore = true
+
+	access.Store(&accessRules{topics: map[string]*TopicConfig{}})
+
+	// don't reindex the topics after the syncs, the store being closed by the test
+	atomic.StoreInt32(&shuttingDown, 1)
+
+	for _, dryRun := range []bool{false, true} {
+		topic := "sync"
+		if dryRun {
+			topic = "dry-run"
+		}
+
+		// the store is up to date with the topic
+		index, err := boltindex.New(db, []byte(topic), false)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		kvs := make(chan KeyValue, topicMessages)
+		for i := 0; i < topicMessages; i++ {
+			kvs <- KeyValue{Key: []byte(fmt.Sprint("key", i)), Value: []byte("v")}
+		}
+		close(kvs)
+
+		resumeKey := make(chan []byte, 1)
+		resumeKey <- []byte(fmt.Sprintf("%16x", topicMessages-1))
+
+		if err = index.Index(kvs, resumeKey); err != nil {
+			t.Fatal(err)
+		}
+
+		source := make(chan KeyValue, 1)
+		source <- KeyValue{Key: []byte("key0"), Value: []byte("v")}
+		close(source)
+
+		spec := &syncSpec{
+			Source:       source,
+			TargetTopic:  topic,
+			DoDelete:     true,
+			Cancel:       make(chan bool),
+			DeleteLimits: deleteLimits{count: 1},
+			DryRun:       dryRun,
+		}
+
+		stats, err := spec.sync()
+
+		if dryRun {
+			if err != nil {
+				t.Fatalf("dry run: unexpected error: %v", err)
+			}
+			if stats.Deleted != 2 {
+				t.Errorf("dry run: %d deletes reported instead of 2", stats.Deleted)
+			}
+			if len(spec.DeleteLimitWarning) == 0 {
+				t.Error("dry run: no deletion limit warning")
+			}
+			continue
+		}
+
+		if err == nil {
+			t.Fatal("sync: no error")
+		}
+
+		if resErr := errorResult(err).Err(); !errors.Is(resErr, client.ErrTooManyDeletes) {
+			t.Errorf("sync: got %v, expected too many deletes", resErr)
+		}
+	}
+}
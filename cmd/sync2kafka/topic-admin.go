@@ -0,0 +1,166 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/Shopify/sarama"
+
+	"github.com/mcluseau/sync2kafka/client"
+)
+
+var (
+	createTopics           = flag.Bool("create-topics", false, "Create missing target topics (compacted); requires kafka-version >= 0.11")
+	topicPartitions        = flag.Int("topic-partitions", 1, "Number of partitions of created topics (more than the topics' synchronized partition)")
+	topicReplicationFactor = flag.Int("topic-replication-factor", 1, "Replication factor of created topics")
+	compactionCheck        = flag.String("compaction-check", "off", "Check that target topics are compacted: off, warn or refuse; requires kafka-version >= 0.11")
+
+	kafkaAdmin sarama.ClusterAdmin
+)
+
+func useKafkaAdmin() bool {
+	return *createTopics || *compactionCheck != "off"
+}
+
+func setupTopicAdmin() {
+	if !useKafkaAdmin() {
+		return
+	}
+
+	var err error
+	kafkaAdmin, err = sarama.NewClusterAdminFromClient(kafka)
+	if err != nil {
+		log.Fatal("failed to create Kafka admin client: ", err)
+	}
+
+	if len(*targetTopic) == 0 {
+		return
+	}
+
+	if err := ensureTopic(*targetTopic, *createTopics); err != nil {
+		// other errors (ie: Kafka unavailable) are checked again by each sync
+		if *compactionCheck == "refuse" && errorResult(err).ErrorCode == client.ErrorCodeTopicNotCompacted {
+			log.Fatal(err)
+		}
+		log.Print("WARNING: ", err)
+	}
+}
+
+// validateTopicAdminConfig checks the topic administration settings.
+func validateTopicAdminConfig(conf *sarama.Config) (errs []error) {
+	switch *compactionCheck {
+	case "off", "warn", "refuse":
+	default:
+		errs = append(errs, fmt.Errorf("invalid compaction-check: %q", *compactionCheck))
+	}
+
+	if *topicPartitions <= 0 {
+		errs = append(errs, errors.New("topic-partitions must be positive"))
+	}
+
+	if *topicReplicationFactor <= 0 || *topicReplicationFactor > 32767 {
+		errs = append(errs, errors.New("topic-replication-factor must be between 1 and 32767"))
+	}
+
+	if useKafkaAdmin() && conf != nil && !conf.Version.IsAtLeast(sarama.V0_11_0_0) {
+		errs = append(errs, errors.New("create-topics and compaction-check require kafka-version >= 0.11"))
+	}
+
+	return
+}
+
+// ensureTopic checks that the topic exists (creating it if allowed) and is compacted.
+//
+// Returned errors are syncErrors, so they can be reported to the client as is.
+func ensureTopic(topic string, create bool) error {
+	if kafkaAdmin == nil {
+		return nil
+	}
+
+	metadata, err := kafkaAdmin.DescribeTopics([]string{topic})
+	if err != nil {
+		return newSyncError(client.ErrorCodeInternal, "failed to describe topic %q: %v", topic, err)
+	}
+
+	exists := len(metadata) != 0 && metadata[0].Err != sarama.ErrUnknownTopicOrPartition
+
+	if exists && metadata[0].Err != sarama.ErrNoError {
+		// ie: not authorized, or leader not available
+		return newSyncError(client.ErrorCodeInternal, "failed to describe topic %q: %v", topic, metadata[0].Err)
+	}
+
+	if !exists {
+		if !create {
+			return newSyncError(client.ErrorCodeTopicNotFound, "topic %q does not exist", topic)
+		}
+
+		return createTopic(topic)
+	}
+
+	if *compactionCheck == "off" {
+		return nil
+	}
+
+	entries, err := kafkaAdmin.DescribeConfig(sarama.ConfigResource{
+		Type:        sarama.TopicResource,
+		Name:        topic,
+		ConfigNames: []string{"cleanup.policy"},
+	})
+	if err != nil {
+		return newSyncError(client.ErrorCodeInternal, "failed to describe topic %q config: %v", topic, err)
+	}
+
+	for _, entry := range entries {
+		if entry.Name != "cleanup.policy" {
+			continue
+		}
+
+		// the policy may be "compact,delete"
+		for _, policy := range strings.Split(entry.Value, ",") {
+			if strings.TrimSpace(policy) == "compact" {
+				return nil
+			}
+		}
+
+		return newSyncError(client.ErrorCodeTopicNotCompacted, "topic %q is not compacted (cleanup.policy=%s)", topic, entry.Value)
+	}
+
+	return newSyncError(client.ErrorCodeTopicNotCompacted, "topic %q has no cleanup.policy", topic)
+}
+
+func createTopic(topic string) error {
+	compact := "compact"
+
+	// the topics' partitions are checked against it by their config validation
+	partitions := int32(*topicPartitions)
+
+	err := kafkaAdmin.CreateTopic(topic, &sarama.TopicDetail{
+		NumPartitions:     partitions,
+		ReplicationFactor: int16(*topicReplicationFactor),
+		ConfigEntries: map[string]*string{
+			"cleanup.policy": &compact,
+		},
+	}, false)
+
+	topicErr := &sarama.TopicError{}
+	if errors.As(err, &topicErr) && topicErr.Err == sarama.ErrTopicAlreadyExists {
+		// created by another instance in the meantime
+		err = nil
+	}
+
+	if err != nil {
+		return newSyncError(client.ErrorCodeInternal, "failed to create topic %q: %v", topic, err)
+	}
+
+	log.Printf("created topic %q (%d partitions, replication factor %d)", topic, partitions, *topicReplicationFactor)
+
+	// make sure the client knows the new topic
+	if err := kafka.RefreshMetadata(topic); err != nil {
+		log.Printf("WARNING: failed to refresh metadata of topic %q: %v", topic, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,331 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Shopify/sarama"
+)
+
+var (
+	lockTopicName = flag.String("lock-topic", "sync2kafka.locks", "Kafka lock backend: compacted topic (1 partition) storing the locks")
+	lockLease     = flag.Duration("lock-lease", 30*time.Second, "Kafka lock backend: lease of a lock, renewed every third of it")
+)
+
+// kafkaLocker shares topic locks between instances through a compacted topic.
+//
+// Each instance records its claims as messages keyed by "<topic>/<owner>". A claim is live
+// until its expiry, and is renewed by its owner while it holds the lock. Releasing a lock
+// is a tombstone, so compaction keeps only the current claims. Every instance consumes the
+// lock topic and applies the same rule: the lock belongs to the live claim with the lowest
+// "since" offset, "since" being the offset of the claim that started the ownership.
+//
+// As with any lease, clocks must be reasonably synchronized compared to the lease duration.
+type kafkaLocker struct {
+	owner    string
+	producer sarama.SyncProducer
+
+	cond    *sync.Cond
+	applied int64
+	claims  map[string]map[string]*lockClaim // topic -> owner -> claim
+
+	held map[string]*heldLock
+}
+
+type lockClaim struct {
+	// Since is the offset of the claim starting the ownership (-1 in a new claim)
+	Since int64 `json:"since"`
+	// Time is the claim's production time
+	Time time.Time `json:"time"`
+	// Expires is the end of the claim's lease
+	Expires time.Time `json:"expires"`
+}
+
+type heldLock struct {
+	since  int64
+	stop   chan bool
+	onLost func()
+}
+
+func newKafkaLocker() *kafkaLocker {
+	partitions, err := kafka.Partitions(*lockTopicName)
+	if err != nil {
+		log.Fatalf("failed to get lock topic %q partitions: %v", *lockTopicName, err)
+	}
+
+	if len(partitions) != 1 {
+		log.Fatalf("lock topic %q must have exactly 1 partition, it has %d", *lockTopicName, len(partitions))
+	}
+
+	producer, err := sarama.NewSyncProducerFromClient(kafka)
+	if err != nil {
+		log.Fatal("failed to create lock producer: ", err)
+	}
+
+	hostname, _ := os.Hostname()
+
+	l := &kafkaLocker{
+		owner:    hostname + "/" + newULID().String(),
+		producer: producer,
+		cond:     sync.NewCond(&sync.Mutex{}),
+		applied:  -1,
+		claims:   map[string]map[string]*lockClaim{},
+		held:     map[string]*heldLock{},
+	}
+
+	consumer, err := sarama.NewConsumerFromClient(kafka)
+	if err != nil {
+		log.Fatal("failed to create lock consumer: ", err)
+	}
+
+	pc, err := consumer.ConsumePartition(*lockTopicName, 0, sarama.OffsetOldest)
+	if err != nil {
+		log.Fatal("failed to consume lock topic: ", err)
+	}
+
+	go l.consume(pc)
+
+	// load the current claims before accepting any lock operation. The last offsets may have
+	// been compacted away, so we wait for a marker (a release of a topic-less claim) instead.
+	marker, err := l.send("", nil)
+	if err != nil {
+		log.Fatal("failed to send lock topic marker: ", err)
+	}
+
+	l.waitApplied(marker)
+
+	log.Printf("kafka locks: ready (owner %s)", l.owner)
+
+	return l
+}
+
+func (l *kafkaLocker) consume(pc sarama.PartitionConsumer) {
+	go func() {
+		for err := range pc.Errors() {
+			log.Print("kafka locks: consumer error: ", err)
+		}
+	}()
+
+	for m := range pc.Messages() {
+		l.apply(m)
+	}
+}
+
+func (l *kafkaLocker) apply(m *sarama.ConsumerMessage) {
+	l.cond.L.Lock()
+	defer l.cond.L.Unlock()
+
+	defer func() {
+		l.applied = m.Offset
+		l.cond.Broadcast()
+	}()
+
+	parts := strings.SplitN(string(m.Key), "/", 2)
+	if len(parts) != 2 {
+		log.Printf("kafka locks: ignoring invalid key %q at offset %d", string(m.Key), m.Offset)
+		return
+	}
+
+	topic, owner := parts[0], parts[1]
+
+	claims := l.claims[topic]
+	if claims == nil {
+		claims = map[string]*lockClaim{}
+		l.claims[topic] = claims
+	}
+
+	if len(m.Value) == 0 {
+		// released
+		delete(claims, owner)
+		if len(claims) == 0 {
+			delete(l.claims, topic)
+		}
+		return
+	}
+
+	claim := &lockClaim{}
+	if err := json.Unmarshal(m.Value, claim); err != nil {
+		log.Printf("kafka locks: ignoring invalid claim at offset %d: %v", m.Offset, err)
+		return
+	}
+
+	if prev := claims[owner]; claim.Since < 0 || (prev != nil && prev.Expires.Before(claim.Time)) {
+		// new claim, or a renewal after the lease expired
+		claim.Since = m.Offset
+	}
+
+	claims[owner] = claim
+
+	// forget long expired claims (ie: from dead instances)
+	for o, c := range claims {
+		if c.Expires.Add(10 * *lockLease).Before(claim.Time) {
+			delete(claims, o)
+		}
+	}
+}
+
+func (l *kafkaLocker) waitApplied(offset int64) {
+	l.cond.L.Lock()
+	defer l.cond.L.Unlock()
+
+	for l.applied < offset {
+		l.cond.Wait()
+	}
+}
+
+// holder returns the current owner of the topic's lock, with the "since" offset of its claim.
+func (l *kafkaLocker) holder(topic string) (owner string, since int64) {
+	l.cond.L.Lock()
+	defer l.cond.L.Unlock()
+
+	now := time.Now()
+	since = -1
+
+	for o, claim := range l.claims[topic] {
+		if claim.Expires.Before(now) {
+			continue
+		}
+
+		if since == -1 || claim.Since < since {
+			owner, since = o, claim.Since
+		}
+	}
+
+	return
+}
+
+func (l *kafkaLocker) send(topic string, claim *lockClaim) (offset int64, err error) {
+	msg := &sarama.ProducerMessage{
+		Topic: *lockTopicName,
+		Key:   sarama.StringEncoder(topic + "/" + l.owner),
+	}
+
+	if claim != nil {
+		ba, err := json.Marshal(claim)
+		if err != nil {
+			return -1, err
+		}
+		msg.Value = sarama.ByteEncoder(ba)
+	}
+
+	_, offset, err = l.producer.SendMessage(msg)
+	return
+}
+
+func (l *kafkaLocker) claim(topic string, since int64) (offset int64, err error) {
+	now := time.Now()
+	return l.send(topic, &lockClaim{
+		Since:   since,
+		Time:    now,
+		Expires: now.Add(*lockLease),
+	})
+}
+
+func (l *kafkaLocker) TryLock(topic string, onLost func()) (bool, error) {
+	h := &heldLock{
+		since:  -1,
+		stop:   make(chan bool),
+		onLost: onLost,
+	}
+
+	// reserve the topic while claiming, our claims being shared by the whole process
+	l.cond.L.Lock()
+	_, held := l.held[topic]
+	if !held {
+		l.held[topic] = h
+	}
+	l.cond.L.Unlock()
+
+	if held {
+		return false, nil
+	}
+
+	offset, err := l.claim(topic, -1)
+	if err == nil {
+		l.waitApplied(offset)
+
+		if owner, since := l.holder(topic); owner == l.owner && since == offset {
+			h.since = offset
+			go l.renew(topic, h)
+			return true, nil
+		}
+
+		// lost, withdraw our claim
+		_, err = l.send(topic, nil)
+	}
+
+	l.cond.L.Lock()
+	delete(l.held, topic)
+	l.cond.L.Unlock()
+
+	return false, err
+}
+
+func (l *kafkaLocker) renew(topic string, h *heldLock) {
+	ticker := time.NewTicker(*lockLease / 3)
+	defer ticker.Stop()
+
+	expires := time.Now().Add(*lockLease)
+
+	for {
+		select {
+		case <-h.stop:
+			return
+
+		case <-ticker.C:
+		}
+
+		renewTime := time.Now()
+
+		offset, err := l.claim(topic, h.since)
+		if err != nil {
+			log.Printf("kafka locks: failed to renew lock on topic %q: %v", topic, err)
+
+			if time.Now().After(expires) {
+				log.Printf("kafka locks: lock on topic %q lost: lease expired", topic)
+				h.onLost()
+				return
+			}
+			continue
+		}
+
+		expires = renewTime.Add(*lockLease)
+
+		l.waitApplied(offset)
+
+		l.cond.L.Lock()
+		if claim := l.claims[topic][l.owner]; claim != nil {
+			// the claim restarts if the lease expired before the renewal
+			h.since = claim.Since
+		}
+		l.cond.L.Unlock()
+
+		if owner, _ := l.holder(topic); owner != l.owner {
+			log.Printf("kafka locks: lock on topic %q lost to %s", topic, owner)
+			h.onLost()
+			return
+		}
+	}
+}
+
+func (l *kafkaLocker) Unlock(topic string) error {
+	l.cond.L.Lock()
+	h, held := l.held[topic]
+	delete(l.held, topic)
+	l.cond.L.Unlock()
+
+	if !held {
+		return fmt.Errorf("topic %q is not locked", topic)
+	}
+
+	close(h.stop)
+
+	_, err := l.send(topic, nil)
+	return err
+}
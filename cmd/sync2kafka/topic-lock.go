@@ -1,33 +1,184 @@
 package main
 
 import (
+	"flag"
 	"log"
 	"runtime"
 	"sync"
+	"time"
 )
 
 var (
+	lockBackend      = flag.String("lock-backend", "memory", "Topic lock backend: memory (single instance) or kafka (shared between instances)")
+	lockPollInterval = flag.Duration("lock-poll-interval", 5*time.Second, "Interval between lock attempts while waiting for a topic lock")
+	maxLockWait      = flag.Duration("max-lock-wait", time.Hour, "Maximum time a connection can wait for a topic lock")
+
+	topicLocks topicLocker
+
 	lockedTopics      = map[string]bool{}
 	lockedTopicsMutex = sync.Mutex{}
+
+	// connections waiting for a topic lock, in arrival order
+	topicWaiters = map[string][]*topicWaiter{}
 )
 
-func lockTopic(topic string) bool {
+// topicLocker is a topic lock backend.
+type topicLocker interface {
+	// TryLock acquires the topic's lock if it's free. onLost is called if the lock is lost
+	// before being released (ie: an expired lease).
+	TryLock(topic string, onLost func()) (bool, error)
+	// Unlock releases the topic's lock.
+	Unlock(topic string) error
+}
+
+type topicWaiter struct {
+	wake       chan bool
+	onPosition func(position int)
+}
+
+func setupLocks() {
+	switch *lockBackend {
+	case "memory":
+		topicLocks = newMemoryLocker()
+
+	case "kafka":
+		topicLocks = newKafkaLocker()
+
+	default:
+		log.Fatalf("unknown lock backend: %q", *lockBackend)
+	}
+
+	log.Printf("using %s topic locks", *lockBackend)
+}
+
+// lockTopic locks the topic if it's free and nobody is waiting for it. onLost is called if the
+// lock is lost before unlockTopic.
+func lockTopic(topic string, onLost func()) bool {
 	lockedTopicsMutex.Lock()
-	defer lockedTopicsMutex.Unlock()
+	queued := len(topicWaiters[topic]) != 0
+	lockedTopicsMutex.Unlock()
 
-	if lockedTopics[topic] {
+	if queued {
 		return false
 	}
 
+	return tryLockTopic(topic, onLost)
+}
+
+func tryLockTopic(topic string, onLost func()) bool {
+	ok, err := topicLocks.TryLock(topic, onLost)
+	if err != nil {
+		log.Printf("failed to lock topic %q: %v", topic, err)
+		return false
+	}
+
+	if !ok {
+		return false
+	}
+
+	lockedTopicsMutex.Lock()
+	defer lockedTopicsMutex.Unlock()
+
 	lockedTopics[topic] = true
+	lockedTopicsGauge.Set(float64(len(lockedTopics)))
 	return true
 }
 
+// waitLockTopic locks the topic, queuing behind other waiters if it's already locked.
+// onPosition is called with the 1-based queue position each time it changes, onLost as in lockTopic.
+// Returns false on timeout or cancellation.
+func waitLockTopic(topic string, timeout time.Duration, cancel <-chan bool, onPosition func(int), onLost func()) bool {
+	w := &topicWaiter{
+		wake:       make(chan bool, 1),
+		onPosition: onPosition,
+	}
+
+	lockedTopicsMutex.Lock()
+	setTopicWaiters(topic, append(topicWaiters[topic], w))
+	lockedTopicsMutex.Unlock()
+
+	defer removeTopicWaiter(topic, w)
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	ticker := time.NewTicker(*lockPollInterval)
+	defer ticker.Stop()
+
+	for {
+		lockedTopicsMutex.Lock()
+		first := topicWaiters[topic][0] == w
+		lockedTopicsMutex.Unlock()
+
+		if first && tryLockTopic(topic, onLost) {
+			onPosition(0)
+			return true
+		}
+
+		select {
+		case <-w.wake:
+		case <-ticker.C:
+		case <-timer.C:
+			return false
+		case <-cancel:
+			return false
+		}
+	}
+}
+
+func removeTopicWaiter(topic string, w *topicWaiter) {
+	lockedTopicsMutex.Lock()
+	defer lockedTopicsMutex.Unlock()
+
+	waiters := topicWaiters[topic]
+	for idx, other := range waiters {
+		if other == w {
+			waiters = append(waiters[:idx:idx], waiters[idx+1:]...)
+			break
+		}
+	}
+	setTopicWaiters(topic, waiters)
+
+	wakeFirstWaiter(topic)
+}
+
+func setTopicWaiters(topic string, waiters []*topicWaiter) {
+	if len(waiters) == 0 {
+		delete(topicWaiters, topic)
+		return
+	}
+
+	topicWaiters[topic] = waiters
+
+	for idx, w := range waiters {
+		w.onPosition(idx + 1)
+	}
+}
+
+func wakeFirstWaiter(topic string) {
+	waiters := topicWaiters[topic]
+	if len(waiters) == 0 {
+		return
+	}
+
+	select {
+	case waiters[0].wake <- true:
+	default: // already woken
+	}
+}
+
 func unlockTopic(topic string) {
+	if err := topicLocks.Unlock(topic); err != nil {
+		log.Printf("failed to unlock topic %q: %v", topic, err)
+	}
+
 	lockedTopicsMutex.Lock()
 	defer lockedTopicsMutex.Unlock()
 
 	delete(lockedTopics, topic)
+	lockedTopicsGauge.Set(float64(len(lockedTopics)))
+
+	wakeFirstWaiter(topic)
 
 	if len(lockedTopics) == 0 {
 		// no more topics sync'ing, let's GC
@@ -38,3 +189,33 @@ func unlockTopic(topic string) {
 		}()
 	}
 }
+
+// memoryLocker locks topics in this process only.
+type memoryLocker struct {
+	mutex  sync.Mutex
+	locked map[string]bool
+}
+
+func newMemoryLocker() *memoryLocker {
+	return &memoryLocker{locked: map[string]bool{}}
+}
+
+func (l *memoryLocker) TryLock(topic string, onLost func()) (bool, error) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if l.locked[topic] {
+		return false, nil
+	}
+
+	l.locked[topic] = true
+	return true, nil
+}
+
+func (l *memoryLocker) Unlock(topic string) error {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	delete(l.locked, topic)
+	return nil
+}
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// topicRules are the allowed topics file entries.
+//
+// Each line is one of:
+//   - a topic name (exact match);
+//   - a glob pattern, like `ref.customers.*` (see path.Match);
+//   - a regular expression between slashes, like `/^ref\.(customers|products)\..+$/`.
+//
+// A `!` prefix makes the entry a deny entry. Deny entries take precedence over allow entries.
+type topicRules struct {
+	allow []topicMatcher
+	deny  []topicMatcher
+}
+
+type topicMatcher interface {
+	Match(topic string) bool
+}
+
+type exactTopic string
+
+func (t exactTopic) Match(topic string) bool {
+	return string(t) == topic
+}
+
+type globTopic string
+
+func (g globTopic) Match(topic string) bool {
+	ok, _ := path.Match(string(g), topic)
+	return ok
+}
+
+type regexpTopic struct {
+	*regexp.Regexp
+}
+
+func (r regexpTopic) Match(topic string) bool {
+	return r.MatchString(topic)
+}
+
+// parseTopicMatcher parses an entry of the allowed topics file (without its `!` prefix).
+func parseTopicMatcher(entry string) (topicMatcher, error) {
+	if len(entry) == 0 {
+		return nil, fmt.Errorf("empty entry")
+	}
+
+	if len(entry) >= 2 && entry[0] == '/' && entry[len(entry)-1] == '/' {
+		re, err := regexp.Compile(entry[1 : len(entry)-1])
+		if err != nil {
+			return nil, err
+		}
+		return regexpTopic{re}, nil
+	}
+
+	if strings.ContainsAny(entry, "*?[") {
+		if _, err := path.Match(entry, ""); err != nil {
+			return nil, fmt.Errorf("invalid glob %q: %v", entry, err)
+		}
+		return globTopic(entry), nil
+	}
+
+	return exactTopic(entry), nil
+}
+
+func loadAllowedTopics(filePath string) (rules *topicRules, err error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return
+	}
+
+	defer file.Close()
+
+	rules = &topicRules{}
+
+	lineNum := 0
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		lineNum++
+
+		line := strings.TrimSpace(scanner.Text())
+		if len(line) == 0 || line[0] == '#' {
+			continue
+		}
+
+		deny := line[0] == '!'
+		if deny {
+			line = strings.TrimSpace(line[1:])
+		}
+
+		m, err := parseTopicMatcher(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %v", lineNum, err)
+		}
+
+		if deny {
+			rules.deny = append(rules.deny, m)
+		} else {
+			rules.allow = append(rules.allow, m)
+		}
+	}
+
+	err = scanner.Err()
+	return
+}
+
+// denies returns true if a deny entry matches the topic.
+func (r *topicRules) denies(topic string) bool {
+	for _, m := range r.deny {
+		if m.Match(topic) {
+			return true
+		}
+	}
+	return false
+}
+
+// allows returns true if an allow entry matches the topic, and no deny entry does.
+func (r *topicRules) allows(topic string) bool {
+	if r.denies(topic) {
+		return false
+	}
+
+	for _, m := range r.allow {
+		if m.Match(topic) {
+			return true
+		}
+	}
+	return false
+}
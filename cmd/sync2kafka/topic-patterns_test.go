@@ -0,0 +1,112 @@
+package main
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+func TestTopicRules(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "allowed-topics.txt")
+	err := ioutil.WriteFile(filePath, []byte(`
+# exact names, globs and regexps
+ref.customers
+ref.products.*
+/^events\.(orders|invoices)\..+$/
+
+! ref.products.secret
+!/\.internal$/
+`), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rules, err := loadAllowedTopics(filePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		topic string
+		allow bool
+	}{
+		{"ref.customers", true},
+		{"ref.customers2", false},
+		{"ref.products.v1", true},
+		{"ref.products", false},
+		{"ref.products.secret", false},    // denied by name
+		{"ref.products.internal", false},  // denied by regexp
+		{"events.orders.internal", false}, // deny takes precedence
+		{"events.orders.v1", true},
+		{"events.invoices.eu", true},
+		{"events.payments.v1", false},
+		{"", false},
+	} {
+		if allow := rules.allows(tc.topic); allow != tc.allow {
+			t.Errorf("%q: allowed=%v, expected %v", tc.topic, allow, tc.allow)
+		}
+	}
+}
+
+func TestParseTopicMatcher(t *testing.T) {
+	for _, tc := range []struct {
+		entry string
+		match string
+		ok    bool
+	}{
+		{"topic", "topic", true},
+		{"a.*", "a.b", true},
+		{"a.?", "a.b", true},
+		{"/^a+$/", "aaa", true},
+		{"/", "/", true}, // too short to be a regexp
+		{"", "", false},
+		{"a[", "", false},
+		{"/(/", "", false},
+	} {
+		m, err := parseTopicMatcher(tc.entry)
+		if (err == nil) != tc.ok {
+			t.Errorf("%q: got error %v", tc.entry, err)
+			continue
+		}
+
+		if err == nil && !m.Match(tc.match) {
+			t.Errorf("%q: doesn't match %q", tc.entry, tc.match)
+		}
+	}
+}
+
+func TestIsTopicAllowed(t *testing.T) {
+	defer access.Store(loadedAccess())
+	defer func(prev bool) { *allowAllTopics = prev }(*allowAllTopics)
+	defer func(prev string) { *targetTopic = prev }(*targetTopic)
+
+	rules := &topicRules{
+		allow: []topicMatcher{globTopic("ref.*")},
+		deny:  []topicMatcher{exactTopic("ref.secret")},
+	}
+
+	*targetTopic = "default"
+
+	for _, tc := range []struct {
+		allowAll bool
+		rules    *topicRules
+		topic    string
+		allow    bool
+	}{
+		{false, nil, "default", true},
+		{false, nil, "other", false},
+		{false, rules, "ref.a", true},
+		{false, rules, "ref.secret", false},
+		{false, rules, "default", false},
+		{true, nil, "other", true},
+		{true, rules, "other", true},
+		{true, rules, "ref.secret", false}, // deny entries still apply
+	} {
+		*allowAllTopics = tc.allowAll
+		access.Store(&accessRules{allowedTopics: tc.rules})
+
+		if allow := isTopicAllowed(tc.topic); allow != tc.allow {
+			t.Errorf("allowAll=%v rules=%v %q: allowed=%v, expected %v", tc.allowAll, tc.rules != nil, tc.topic, allow, tc.allow)
+		}
+	}
+}
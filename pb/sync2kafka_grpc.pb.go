@@ -0,0 +1,333 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             (unknown)
+// source: sync2kafka.proto
+
+package pb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	Sync2Kafka_Sync_FullMethodName             = "/sync2kafka.Sync2Kafka/Sync"
+	Sync2Kafka_ListConnections_FullMethodName  = "/sync2kafka.Sync2Kafka/ListConnections"
+	Sync2Kafka_GetConnection_FullMethodName    = "/sync2kafka.Sync2Kafka/GetConnection"
+	Sync2Kafka_CancelConnection_FullMethodName = "/sync2kafka.Sync2Kafka/CancelConnection"
+	Sync2Kafka_CancelTopicSync_FullMethodName  = "/sync2kafka.Sync2Kafka/CancelTopicSync"
+	Sync2Kafka_IndexTopic_FullMethodName       = "/sync2kafka.Sync2Kafka/IndexTopic"
+)
+
+// Sync2KafkaClient is the client API for Sync2Kafka service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type Sync2KafkaClient interface {
+	// Sync synchronizes a topic: the first message is the init, the next ones are the values.
+	Sync(ctx context.Context, opts ...grpc.CallOption) (Sync2Kafka_SyncClient, error)
+	ListConnections(ctx context.Context, in *ListConnectionsRequest, opts ...grpc.CallOption) (*ConnectionList, error)
+	GetConnection(ctx context.Context, in *ConnectionRef, opts ...grpc.CallOption) (*Connection, error)
+	CancelConnection(ctx context.Context, in *ConnectionRef, opts ...grpc.CallOption) (*Connection, error)
+	CancelTopicSync(ctx context.Context, in *TopicRef, opts ...grpc.CallOption) (*Connection, error)
+	// IndexTopic reindexes a topic in the server's store.
+	IndexTopic(ctx context.Context, in *TopicRef, opts ...grpc.CallOption) (*IndexTopicResult, error)
+}
+
+type sync2KafkaClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewSync2KafkaClient(cc grpc.ClientConnInterface) Sync2KafkaClient {
+	return &sync2KafkaClient{cc}
+}
+
+func (c *sync2KafkaClient) Sync(ctx context.Context, opts ...grpc.CallOption) (Sync2Kafka_SyncClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Sync2Kafka_ServiceDesc.Streams[0], Sync2Kafka_Sync_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &sync2KafkaSyncClient{stream}
+	return x, nil
+}
+
+type Sync2Kafka_SyncClient interface {
+	Send(*SyncRequest) error
+	CloseAndRecv() (*SyncResult, error)
+	grpc.ClientStream
+}
+
+type sync2KafkaSyncClient struct {
+	grpc.ClientStream
+}
+
+func (x *sync2KafkaSyncClient) Send(m *SyncRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *sync2KafkaSyncClient) CloseAndRecv() (*SyncResult, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(SyncResult)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *sync2KafkaClient) ListConnections(ctx context.Context, in *ListConnectionsRequest, opts ...grpc.CallOption) (*ConnectionList, error) {
+	out := new(ConnectionList)
+	err := c.cc.Invoke(ctx, Sync2Kafka_ListConnections_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sync2KafkaClient) GetConnection(ctx context.Context, in *ConnectionRef, opts ...grpc.CallOption) (*Connection, error) {
+	out := new(Connection)
+	err := c.cc.Invoke(ctx, Sync2Kafka_GetConnection_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sync2KafkaClient) CancelConnection(ctx context.Context, in *ConnectionRef, opts ...grpc.CallOption) (*Connection, error) {
+	out := new(Connection)
+	err := c.cc.Invoke(ctx, Sync2Kafka_CancelConnection_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sync2KafkaClient) CancelTopicSync(ctx context.Context, in *TopicRef, opts ...grpc.CallOption) (*Connection, error) {
+	out := new(Connection)
+	err := c.cc.Invoke(ctx, Sync2Kafka_CancelTopicSync_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sync2KafkaClient) IndexTopic(ctx context.Context, in *TopicRef, opts ...grpc.CallOption) (*IndexTopicResult, error) {
+	out := new(IndexTopicResult)
+	err := c.cc.Invoke(ctx, Sync2Kafka_IndexTopic_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Sync2KafkaServer is the server API for Sync2Kafka service.
+// All implementations must embed UnimplementedSync2KafkaServer
+// for forward compatibility
+type Sync2KafkaServer interface {
+	// Sync synchronizes a topic: the first message is the init, the next ones are the values.
+	Sync(Sync2Kafka_SyncServer) error
+	ListConnections(context.Context, *ListConnectionsRequest) (*ConnectionList, error)
+	GetConnection(context.Context, *ConnectionRef) (*Connection, error)
+	CancelConnection(context.Context, *ConnectionRef) (*Connection, error)
+	CancelTopicSync(context.Context, *TopicRef) (*Connection, error)
+	// IndexTopic reindexes a topic in the server's store.
+	IndexTopic(context.Context, *TopicRef) (*IndexTopicResult, error)
+	mustEmbedUnimplementedSync2KafkaServer()
+}
+
+// UnimplementedSync2KafkaServer must be embedded to have forward compatible implementations.
+type UnimplementedSync2KafkaServer struct {
+}
+
+func (UnimplementedSync2KafkaServer) Sync(Sync2Kafka_SyncServer) error {
+	return status.Errorf(codes.Unimplemented, "method Sync not implemented")
+}
+func (UnimplementedSync2KafkaServer) ListConnections(context.Context, *ListConnectionsRequest) (*ConnectionList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListConnections not implemented")
+}
+func (UnimplementedSync2KafkaServer) GetConnection(context.Context, *ConnectionRef) (*Connection, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetConnection not implemented")
+}
+func (UnimplementedSync2KafkaServer) CancelConnection(context.Context, *ConnectionRef) (*Connection, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CancelConnection not implemented")
+}
+func (UnimplementedSync2KafkaServer) CancelTopicSync(context.Context, *TopicRef) (*Connection, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CancelTopicSync not implemented")
+}
+func (UnimplementedSync2KafkaServer) IndexTopic(context.Context, *TopicRef) (*IndexTopicResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method IndexTopic not implemented")
+}
+func (UnimplementedSync2KafkaServer) mustEmbedUnimplementedSync2KafkaServer() {}
+
+// UnsafeSync2KafkaServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to Sync2KafkaServer will
+// result in compilation errors.
+type UnsafeSync2KafkaServer interface {
+	mustEmbedUnimplementedSync2KafkaServer()
+}
+
+func RegisterSync2KafkaServer(s grpc.ServiceRegistrar, srv Sync2KafkaServer) {
+	s.RegisterService(&Sync2Kafka_ServiceDesc, srv)
+}
+
+func _Sync2Kafka_Sync_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(Sync2KafkaServer).Sync(&sync2KafkaSyncServer{stream})
+}
+
+type Sync2Kafka_SyncServer interface {
+	SendAndClose(*SyncResult) error
+	Recv() (*SyncRequest, error)
+	grpc.ServerStream
+}
+
+type sync2KafkaSyncServer struct {
+	grpc.ServerStream
+}
+
+func (x *sync2KafkaSyncServer) SendAndClose(m *SyncResult) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *sync2KafkaSyncServer) Recv() (*SyncRequest, error) {
+	m := new(SyncRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _Sync2Kafka_ListConnections_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListConnectionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(Sync2KafkaServer).ListConnections(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Sync2Kafka_ListConnections_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(Sync2KafkaServer).ListConnections(ctx, req.(*ListConnectionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Sync2Kafka_GetConnection_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ConnectionRef)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(Sync2KafkaServer).GetConnection(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Sync2Kafka_GetConnection_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(Sync2KafkaServer).GetConnection(ctx, req.(*ConnectionRef))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Sync2Kafka_CancelConnection_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ConnectionRef)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(Sync2KafkaServer).CancelConnection(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Sync2Kafka_CancelConnection_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(Sync2KafkaServer).CancelConnection(ctx, req.(*ConnectionRef))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Sync2Kafka_CancelTopicSync_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TopicRef)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(Sync2KafkaServer).CancelTopicSync(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Sync2Kafka_CancelTopicSync_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(Sync2KafkaServer).CancelTopicSync(ctx, req.(*TopicRef))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Sync2Kafka_IndexTopic_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TopicRef)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(Sync2KafkaServer).IndexTopic(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Sync2Kafka_IndexTopic_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(Sync2KafkaServer).IndexTopic(ctx, req.(*TopicRef))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Sync2Kafka_ServiceDesc is the grpc.ServiceDesc for Sync2Kafka service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Sync2Kafka_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "sync2kafka.Sync2Kafka",
+	HandlerType: (*Sync2KafkaServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListConnections",
+			Handler:    _Sync2Kafka_ListConnections_Handler,
+		},
+		{
+			MethodName: "GetConnection",
+			Handler:    _Sync2Kafka_GetConnection_Handler,
+		},
+		{
+			MethodName: "CancelConnection",
+			Handler:    _Sync2Kafka_CancelConnection_Handler,
+		},
+		{
+			MethodName: "CancelTopicSync",
+			Handler:    _Sync2Kafka_CancelTopicSync_Handler,
+		},
+		{
+			MethodName: "IndexTopic",
+			Handler:    _Sync2Kafka_IndexTopic_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Sync",
+			Handler:       _Sync2Kafka_Sync_Handler,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "sync2kafka.proto",
+}
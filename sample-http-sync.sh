@@ -0,0 +1,8 @@
+#! /bin/sh
+
+curl -sS -XPOST -H "Authorization: Bearer test-token" --data-binary @- \
+    "http://localhost:8080/topics/sync2kafka.test2/sync?format=json&doDelete=true" <<EOF2
+{"k":{"id":1000},"v":{"name":"test id 1000","date":"$(date)"}}
+{"k":{"id":1001},"v":{"name":"test id 1001"}}
+{"k":{"timestamp":"$(date +%s)"},"v":{"name":"test timestamp"}}
+EOF2